@@ -0,0 +1,110 @@
+package applogger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apacheTimeLayout is the %t timestamp layout used by Apache
+const apacheTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// apacheCombinedRegexp matches a single Apache Combined Log Format line
+var apacheCombinedRegexp = regexp.MustCompile(`^(\S+) \S+ (\S+) \[([^\]]+)\] "(\S+) (\S+) ([^"]*)" (\d{3}) (\d+|-) "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)"$`)
+
+// apacheCombined formats the request as an Apache Combined Log Format line
+// host ident authuser [date] "request" status bytes "referer" "user-agent"
+func apacheCombined(e GinLogEntry, useUTC bool) string {
+	start := e.start
+	if useUTC {
+		start = start.UTC()
+	}
+
+	size := "-"
+	if e.size > 0 {
+		size = strconv.Itoa(e.size)
+	}
+
+	return fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s "%s" "%s"`,
+		apacheField(e.ClientIP),
+		apacheField(e.user),
+		apacheTimestamps.format(start),
+		e.Method, e.uri, e.proto,
+		e.Status,
+		size,
+		apacheField(e.referer),
+		apacheField(e.UserAgent),
+	)
+}
+
+// apacheField replaces empty values with "-" and escapes quotes
+func apacheField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// apacheValue reverses apacheField
+func apacheValue(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(s)
+}
+
+// ParseApacheCombinedLog reads Apache Combined Log Format lines back into log entries.
+// The level of each entry is derived from the status code the same way GinLogger does.
+func ParseApacheCombinedLog(r io.Reader) ([]*LogEntry, error) {
+	var entries []*LogEntry
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		m := apacheCombinedRegexp.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("applogger: line %d is not in Apache Combined Log Format", line)
+		}
+
+		timestamp, err := time.Parse(apacheTimeLayout, m[3])
+		if err != nil {
+			return nil, fmt.Errorf("applogger: line %d: %s", line, err)
+		}
+
+		status, _ := strconv.Atoi(m[7])
+		var size int64
+		if m[8] != "-" {
+			size, _ = strconv.ParseInt(m[8], 10, 64)
+		}
+
+		entries = append(entries, &LogEntry{
+			Level:     levelForStatus(status),
+			Timestamp: timestamp,
+			Message:   fmt.Sprintf("%s %s %s", m[4], m[5], m[6]),
+			Fields: []Field{
+				{Key: "remote_addr", Value: m[1]},
+				{Key: "user", Value: apacheValue(m[2])},
+				{Key: "method", Value: m[4]},
+				{Key: "path", Value: m[5]},
+				{Key: "protocol", Value: m[6]},
+				{Key: "status", Value: status},
+				{Key: "bytes", Value: size},
+				{Key: "referer", Value: apacheValue(m[9])},
+				{Key: "user_agent", Value: apacheValue(m[10])},
+			},
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,102 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseApacheCombinedLogRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatApacheCombined}
+	l.StartWriter(LevelInfo, &bytes.Buffer{})
+
+	r := gin.New()
+	r.Use(l.GinLoggerWithConfig(GinLoggerConfig{Output: &buf}))
+	r.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusNotFound, "missing") })
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42?full=1", nil)
+	req.Header.Set("Referer", "http://example.com/")
+	req.Header.Set("User-Agent", `curl "quoted"`)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries, err := ParseApacheCombinedLog(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("%d entries, want 1", len(entries))
+	}
+
+	e := entries[0]
+	if e.Level != LevelWarn || e.Message != "GET /users/42?full=1 HTTP/1.1" || time.Since(e.Timestamp) > time.Minute {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	want := map[string]interface{}{
+		"user":       "",
+		"method":     http.MethodGet,
+		"path":       "/users/42?full=1",
+		"protocol":   "HTTP/1.1",
+		"status":     http.StatusNotFound,
+		"bytes":      int64(len("missing")),
+		"referer":    "http://example.com/",
+		"user_agent": `curl "quoted"`,
+	}
+	for _, f := range e.Fields {
+		if v, ok := want[f.Key]; ok && v != f.Value {
+			t.Errorf("%s = %#v, want %#v", f.Key, f.Value, v)
+		}
+	}
+}
+
+func TestParseApacheCombinedLogDashes(t *testing.T) {
+	line := `10.0.0.1 - - [02/Jan/2006:15:04:05 -0700] "HEAD / HTTP/1.0" 500 - "-" "-"` + "\n\n"
+	entries, err := ParseApacheCombinedLog(strings.NewReader(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("%d entries, want 1", len(entries))
+	}
+
+	e := entries[0]
+	if e.Level != LevelError || !e.Timestamp.Equal(time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	for _, f := range e.Fields {
+		switch f.Key {
+		case "user", "referer", "user_agent":
+			if f.Value != "" {
+				t.Errorf("%s = %q, want empty for -", f.Key, f.Value)
+			}
+		case "bytes":
+			if f.Value != int64(0) {
+				t.Errorf("bytes = %v, want 0 for -", f.Value)
+			}
+		}
+	}
+}
+
+func TestParseApacheCombinedLogMalformed(t *testing.T) {
+	for _, line := range []string{
+		"not an access log line",
+		`10.0.0.1 - - [02/Jan/2006:15:04:05 -0700] "GET / HTTP/1.1" 200 12`,
+		`10.0.0.1 - - [yesterday] "GET / HTTP/1.1" 200 12 "-" "-"`,
+	} {
+		good := `10.0.0.1 - - [02/Jan/2006:15:04:05 -0700] "GET / HTTP/1.1" 200 12 "-" "-"`
+		entries, err := ParseApacheCombinedLog(strings.NewReader(good + "\n" + line + "\n"))
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("%q: %v, %d entries, want an error for line 2", line, err, len(entries))
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package applogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveError lists the paths LogDirectoryArchive failed to archive
+type ArchiveError struct {
+	Errors []error
+}
+
+// Error joins the errors of the failed paths, one per line
+func (e *ArchiveError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// LogDirectoryArchive moves the date directories and log files LogDirectoryCleanup
+// would remove from baseFilePath to archivePath instead, the paths DryRunCleanup
+// returns. With compress the log files moved are gzipped. A path that fails is
+// left in place and the others are still archived, the failures are returned
+// as an *ArchiveError.
+func (l *Logger) LogDirectoryArchive(baseFilePath, archivePath string, daysToKeep int, compress bool) error {
+	l.Startedf("LogDirectoryArchive", "BaseFilePath[%s] ArchivePath[%s] DaysToKeep[%d]", baseFilePath, archivePath, daysToKeep)
+
+	paths, err := l.DryRunCleanup(baseFilePath, daysToKeep)
+	if err != nil {
+		l.CompletedError("LogDirectoryArchive", err)
+		return err
+	}
+
+	if err := os.MkdirAll(archivePath, 0777); err != nil {
+		l.CompletedError("LogDirectoryArchive", err)
+		return err
+	}
+
+	var failed []error
+	for _, path := range paths {
+		target := filepath.Join(archivePath, filepath.Base(path))
+
+		l.Debug("LogDirectoryArchive : Archiving [%s] To [%s]", path, target)
+
+		if err := movePath(path, target); err != nil {
+			l.ErrorWith(err, "LogDirectoryArchive : Failed to Archive [%s] :", path)
+			failed = append(failed, fmt.Errorf("archive %s: %s", path, err))
+			continue
+		}
+
+		if compress {
+			if err := compressTree(target); err != nil {
+				l.ErrorWith(err, "LogDirectoryArchive : Failed to Compress [%s] :", target)
+				failed = append(failed, fmt.Errorf("compress %s: %s", target, err))
+				continue
+			}
+		}
+
+		l.Debug("LogDirectoryArchive : Archived [%s]", path)
+	}
+
+	if len(failed) > 0 {
+		err := &ArchiveError{Errors: failed}
+		l.CompletedError("LogDirectoryArchive", err)
+		return err
+	}
+
+	l.Completed("LogDirectoryArchive")
+	return nil
+}
+
+// movePath renames path to target, or copies it and removes path when the
+// archive is on another file system
+func movePath(path, target string) error {
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Errorf("%s exists already", target)
+	}
+
+	if err := os.Rename(path, target); err == nil {
+		return nil
+	}
+
+	if err := copyTree(path, target); err != nil {
+		os.RemoveAll(target)
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// copyTree copies the file or directory at path to target
+func copyTree(path, target string) error {
+	return filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(path, name)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(target, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(dst, info.Mode().Perm())
+		}
+		return copyFile(name, dst, info)
+	})
+}
+
+// copyFile copies the regular file src to dst, keeping its mode and time
+func copyFile(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// compressTree gzips the files at path that are not compressed yet
+func compressTree(path string) error {
+	return filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || strings.HasSuffix(name, ".gz") {
+			return nil
+		}
+		return CompressFile(name)
+	})
+}
@@ -0,0 +1,105 @@
+package applogger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dayDir names the date directory of days ago
+func dayDir(days int) string {
+	return time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+}
+
+// writeLogs creates a date directory with a log file in dir for each of days ago
+func writeLogs(t *testing.T, dir string, days ...int) {
+	t.Helper()
+
+	for _, d := range days {
+		sub := filepath.Join(dir, dayDir(d))
+		if err := os.MkdirAll(sub, 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(sub, "app.txt"), []byte("day "+dayDir(d)+"\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLogDirectoryArchive(t *testing.T) {
+	base, archive := tempDir(t), filepath.Join(tempDir(t), "archive")
+	writeLogs(t, base, 0, 10, 20)
+
+	old := filepath.Join(base, "old.log")
+	if err := ioutil.WriteFile(old, []byte("loose\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().AddDate(0, 0, -30)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	l := Discard()
+	if err := l.LogDirectoryArchive(base, archive, 5, true); err != nil {
+		t.Fatal(err)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	if _, err := os.Stat(filepath.Join(base, today, "app.txt")); err != nil {
+		t.Errorf("the directory of today was archived: %s", err)
+	}
+
+	for _, d := range []int{10, 20} {
+		name := time.Now().AddDate(0, 0, -d).Format("2006-01-02")
+		if _, err := os.Stat(filepath.Join(base, name)); !os.IsNotExist(err) {
+			t.Errorf("%s is still in the base path", name)
+		}
+		if _, err := os.Stat(filepath.Join(archive, name, "app.txt.gz")); err != nil {
+			t.Errorf("%s is not archived compressed: %s", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(archive, "old.log.gz")); err != nil {
+		t.Errorf("the loose log file is not archived: %s", err)
+	}
+}
+
+func TestLogDirectoryArchiveFailure(t *testing.T) {
+	base, archive := tempDir(t), tempDir(t)
+	writeLogs(t, base, 10, 20)
+
+	// The archive holds a directory of the same name already
+	taken := time.Now().AddDate(0, 0, -10).Format("2006-01-02")
+	if err := os.Mkdir(filepath.Join(archive, taken), 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Discard().LogDirectoryArchive(base, archive, 5, false)
+	archiveErr, ok := err.(*ArchiveError)
+	if !ok || len(archiveErr.Errors) != 1 || !strings.Contains(archiveErr.Error(), taken) {
+		t.Fatalf("LogDirectoryArchive = %v, want an ArchiveError for %s", err, taken)
+	}
+
+	if _, err := os.Stat(filepath.Join(base, taken, "app.txt")); err != nil {
+		t.Errorf("the failed directory was not left in place: %s", err)
+	}
+	other := time.Now().AddDate(0, 0, -20).Format("2006-01-02")
+	if content := readFile(t, filepath.Join(archive, other, "app.txt")); !strings.Contains(content, other) {
+		t.Errorf("the other directory was not archived: %q", content)
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	src, dst := tempDir(t), filepath.Join(tempDir(t), "copy")
+	writeLogs(t, src, 3)
+
+	if err := copyTree(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	name := time.Now().AddDate(0, 0, -3).Format("2006-01-02")
+	if content := readFile(t, filepath.Join(dst, name, "app.txt")); !strings.Contains(content, name) {
+		t.Errorf("copied file: %q", content)
+	}
+}
@@ -0,0 +1,218 @@
+package applogger
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultAsyncBufferSize is the queue depth when AsyncBufferSize is not set
+const defaultAsyncBufferSize = 1024
+
+// BackPressurePolicy decides what logging does while the Async queue is full
+type BackPressurePolicy int
+
+const (
+	// BackPressureBlock makes logging wait for room in the queue, no line is lost
+	BackPressureBlock BackPressurePolicy = iota
+
+	// BackPressureDrop drops the lines logged while the queue is full
+	BackPressureDrop
+
+	// BackPressureSample drops half of the lines, chosen at random, once the
+	// queue is 80% full and every line while it is full
+	BackPressureSample
+)
+
+// AsyncStats reports the state of the Async queue
+type AsyncStats struct {
+	// Queued is the number of lines waiting to be written
+	Queued int
+	// Dropped is the number of lines dropped by the BackPressurePolicy
+	Dropped uint64
+}
+
+// asyncItem is a line waiting to be written to w
+type asyncItem struct {
+	level int32
+	w     io.Writer
+	p     []byte
+}
+
+// asyncQueue holds the lines of every level in the order they were logged
+// until a background goroutine writes them. Once Stop closed the queue the
+// lines are written directly.
+type asyncQueue struct {
+	a      *ApplicationLog
+	policy BackPressurePolicy
+
+	mu      sync.RWMutex
+	closed  bool
+	items   chan asyncItem
+	dropped uint64
+}
+
+// asyncLevelWriter queues the lines of a level
+type asyncLevelWriter struct {
+	q     *asyncQueue
+	level int32
+	w     io.Writer
+}
+
+// startAsync returns the open queue of the ApplicationLog, creating it and
+// starting its writer when there is none
+func (a *ApplicationLog) startAsync(size int, flushInterval time.Duration, policy BackPressurePolicy) *asyncQueue {
+	a.mu.Lock()
+	q := a.async
+	if q != nil && !q.isClosed() {
+		a.mu.Unlock()
+		return q
+	}
+
+	if size <= 0 {
+		size = defaultAsyncBufferSize
+	}
+	q = &asyncQueue{a: a, policy: policy, items: make(chan asyncItem, size)}
+	a.async = q
+	a.mu.Unlock()
+
+	a.background(func(stopped <-chan struct{}) {
+		q.run(stopped, flushInterval)
+	})
+	return q
+}
+
+// writer wraps the writer of a level so its lines go through the queue
+func (q *asyncQueue) writer(level int32, w io.Writer) io.Writer {
+	if w == ioutil.Discard {
+		return w
+	}
+	return &asyncLevelWriter{q: q, level: level, w: w}
+}
+
+// isClosed reports whether the lines are written directly
+func (q *asyncQueue) isClosed() bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.closed
+}
+
+// Write queues a copy of p, while the queue is full it blocks or drops p
+// following the BackPressurePolicy
+func (aw *asyncLevelWriter) Write(p []byte) (int, error) {
+	aw.q.mu.RLock()
+	if aw.q.closed {
+		aw.q.mu.RUnlock()
+		return aw.w.Write(p)
+	}
+
+	aw.q.push(asyncItem{level: aw.level, w: aw.w, p: append([]byte(nil), p...)})
+	aw.q.mu.RUnlock()
+	return len(p), nil
+}
+
+// push queues item following the BackPressurePolicy
+func (q *asyncQueue) push(item asyncItem) {
+	if q.policy == BackPressureBlock {
+		q.items <- item
+		return
+	}
+
+	// BackPressureSample thins the lines out before the queue is full
+	if q.policy == BackPressureSample && len(q.items)*5 >= cap(q.items)*4 && sampleFloat() < 0.5 {
+		atomic.AddUint64(&q.dropped, 1)
+		return
+	}
+
+	select {
+	case q.items <- item:
+	default:
+		atomic.AddUint64(&q.dropped, 1)
+	}
+}
+
+// Stats returns the number of queued and dropped Async lines, it is zero
+// when Async is not set
+func (l *Logger) Stats() AsyncStats {
+	a := l.instance()
+
+	a.mu.RLock()
+	q := a.async
+	a.mu.RUnlock()
+
+	if q == nil {
+		return AsyncStats{}
+	}
+	return AsyncStats{Queued: len(q.items), Dropped: atomic.LoadUint64(&q.dropped)}
+}
+
+// run writes the queued lines until stopped is closed, then closes the queue
+// and writes what is left. With a flushInterval the lines are collected and
+// written every interval or once the queue depth is reached.
+func (q *asyncQueue) run(stopped <-chan struct{}, flushInterval time.Duration) {
+	var pending []asyncItem
+	flush := func() {
+		for _, item := range pending {
+			if _, err := item.w.Write(item.p); err != nil {
+				q.a.writeFailed(item.level, err)
+			}
+		}
+		pending = pending[:0]
+	}
+	add := func(item asyncItem) {
+		pending = append(pending, item)
+		if flushInterval <= 0 || len(pending) >= cap(q.items) {
+			flush()
+		}
+	}
+
+	var tick <-chan time.Time
+	if flushInterval > 0 {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case item := <-q.items:
+			add(item)
+		case <-tick:
+			flush()
+		case <-stopped:
+			q.close(add)
+			flush()
+			return
+		}
+	}
+}
+
+// close stops queueing new lines. Writers blocked on a full queue hold the
+// read lock, so the queue is drained while waiting for the write lock.
+func (q *asyncQueue) close(add func(asyncItem)) {
+	closed := make(chan struct{})
+	go func() {
+		q.mu.Lock()
+		q.closed = true
+		q.mu.Unlock()
+		close(closed)
+	}()
+
+	for {
+		select {
+		case item := <-q.items:
+			add(item)
+		case <-closed:
+			for {
+				select {
+				case item := <-q.items:
+					add(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter blocks every write until release is closed
+type blockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+	buf     syncBuffer
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	select {
+	case w.started <- struct{}{}:
+	default:
+	}
+	<-w.release
+	return w.buf.Write(p)
+}
+
+func TestAsyncBackPressureDrop(t *testing.T) {
+	w := newBlockingWriter()
+	l := &Logger{FileLogLevel: LevelInfo, Async: true, AsyncBufferSize: 2, AsyncBackPressure: BackPressureDrop}
+	l.StartWriter(LevelError, w)
+
+	// the first line is taken by the writer goroutine and blocks it
+	l.Info("line 0")
+	<-w.started
+	for i := 1; i <= 10; i++ {
+		l.Info("line %d", i)
+	}
+
+	stats := l.Stats()
+	if stats.Queued != 2 || stats.Dropped != 8 {
+		t.Errorf("Stats = %+v, want 2 queued and 8 dropped", stats)
+	}
+
+	close(w.release)
+	l.Stop()
+
+	out := w.buf.String()
+	for _, line := range []string{"line 0", "line 1", "line 2"} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("%s is missing from\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "line 3\n") {
+		t.Errorf("a dropped line was written\n%s", out)
+	}
+}
+
+func TestAsyncBackPressureSample(t *testing.T) {
+	w := newBlockingWriter()
+	l := &Logger{FileLogLevel: LevelInfo, Async: true, AsyncBufferSize: 100, AsyncBackPressure: BackPressureSample}
+	l.StartWriter(LevelError, w)
+
+	l.Info("line 0")
+	<-w.started
+	// up to 80 lines are queued, then about half of them
+	for i := 1; i <= 200; i++ {
+		l.Info("line %d", i)
+	}
+
+	stats := l.Stats()
+	if stats.Queued+int(stats.Dropped) != 200 {
+		t.Errorf("Stats = %+v, the lines do not add up to 200", stats)
+	}
+	if stats.Queued < 80 || stats.Dropped < 100 {
+		t.Errorf("Stats = %+v, want at least 80 queued and 100 dropped", stats)
+	}
+
+	close(w.release)
+	l.Stop()
+}
+
+func TestAsyncBackPressureBlock(t *testing.T) {
+	w := newBlockingWriter()
+	l := &Logger{FileLogLevel: LevelInfo, Async: true, AsyncBufferSize: 1}
+	l.StartWriter(LevelError, w)
+
+	l.Info("line 0")
+	<-w.started
+	l.Info("line 1")
+
+	done := make(chan struct{})
+	go func() {
+		l.Info("line 2")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("logging did not block on the full queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(w.release)
+	<-done
+	l.Stop()
+
+	if stats := l.Stats(); stats.Dropped != 0 {
+		t.Errorf("Stats = %+v, BackPressureBlock dropped lines", stats)
+	}
+	if out := w.buf.String(); !strings.Contains(out, "line 2\n") {
+		t.Errorf("line 2 is missing from\n%s", out)
+	}
+}
+
+func TestAsyncStopDrains(t *testing.T) {
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo, Async: true, AsyncBufferSize: 16, FlushInterval: time.Millisecond}
+	a, err := l.TryStartFile(LevelError, tempDir(t), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := a.LogFile.Name()
+
+	const goroutines, lines = 8, 500
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				l.Info("goroutine %d line %d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	l.Stop()
+
+	written := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(readFile(t, path)), "\n") {
+		written[line[strings.LastIndex(line, ": ")+2:]] = true
+	}
+	for g := 0; g < goroutines; g++ {
+		for i := 0; i < lines; i++ {
+			if line := fmt.Sprintf("goroutine %d line %d", g, i); !written[line] {
+				t.Fatalf("%s is missing after Stop, %d lines written", line, len(written))
+			}
+		}
+	}
+}
+
+func TestAsyncFlushInterval(t *testing.T) {
+	var buf syncBuffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo, Async: true, FlushInterval: 5 * time.Millisecond}
+	l.StartWriter(LevelError, &buf)
+	defer l.Stop()
+
+	l.Info("flushed without Stop")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), "flushed without Stop") {
+		if time.Now().After(deadline) {
+			t.Fatal("the queued line was not written by the FlushInterval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,67 @@
+package applogger
+
+// SetBuildInfo adds the application version, the build commit and the Go version
+// the binary was built with to every line of the default ApplicationLog, e.g. from
+// variables set with -ldflags "-X main.version=1.2.3". Empty values are left out.
+// The loggers started afterwards write it as well, unless they set AppVersion
+// or BuildCommit, so it can be called before Start.
+func SetBuildInfo(version, commit, goVersion string) {
+	fields := buildInfoFields(version, commit, goVersion)
+
+	stdBuildInfo.Lock()
+	stdBuildInfo.fields = fields
+	stdBuildInfo.Unlock()
+
+	a := Default()
+	a.mu.Lock()
+	a.buildInfo = fields
+	a.mu.Unlock()
+}
+
+// setBuildInfo sets the build info fields written with every line
+func (a *ApplicationLog) setBuildInfo(version, commit, goVersion string) {
+	fields := buildInfoFields(version, commit, goVersion)
+
+	a.mu.Lock()
+	a.buildInfo = fields
+	a.mu.Unlock()
+}
+
+// inheritBuildInfo writes the build info of SetBuildInfo, unless a has its own
+func (a *ApplicationLog) inheritBuildInfo() {
+	stdBuildInfo.RLock()
+	fields := stdBuildInfo.fields
+	stdBuildInfo.RUnlock()
+
+	a.mu.Lock()
+	if a.buildInfo == nil {
+		a.buildInfo = fields
+	}
+	a.mu.Unlock()
+}
+
+// buildInfoFields returns the fields of the build info values that are set
+func buildInfoFields(version, commit, goVersion string) []Field {
+	var fields []Field
+	if version != "" {
+		fields = append(fields, Field{Key: "app_version", Value: version})
+	}
+	if commit != "" {
+		fields = append(fields, Field{Key: "build_commit", Value: commit})
+	}
+	if goVersion != "" {
+		fields = append(fields, Field{Key: "go_version", Value: goVersion})
+	}
+	return fields
+}
+
+// withBuildInfo returns fields followed by the build info fields
+func withBuildInfo(fields, buildInfo []Field) []Field {
+	if len(buildInfo) == 0 {
+		return fields
+	}
+
+	all := make([]Field, 0, len(fields)+len(buildInfo))
+	all = append(all, fields...)
+	return append(all, buildInfo...)
+}
@@ -0,0 +1,104 @@
+package applogger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CEFConfig names the device reporting FormatCEF events
+type CEFConfig struct {
+	// Vendor defaults to codingmechanics
+	Vendor string
+	// Product defaults to applogger
+	Product string
+	// Version of the reporting application
+	Version string
+}
+
+// escaping rules from the CEF specification. The header only needs the
+// pipe escaped, extension values need the equal sign and new lines.
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// cefSeverity maps a level to the 0-10 CEF severity scale
+func cefSeverity(level int32) int {
+	switch level {
+	case LevelTrace:
+		return 0
+	case LevelDebug:
+		return 1
+	case LevelInfo:
+		return 3
+	case LevelWarn:
+		return 6
+	case LevelFatal:
+		return 10
+	default:
+		return 8
+	}
+}
+
+// cefSignature is the signature id reported for a level
+func cefSignature(level int32) string {
+	switch level {
+	case LevelTrace:
+		return "TRACE"
+	case LevelFatal:
+		return "FATAL"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+// cefEvent formats the entry as a single CEF event
+// CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
+func cefEvent(e *LogEntry, cfg CEFConfig) string {
+	vendor := cfg.Vendor
+	if vendor == "" {
+		vendor = "codingmechanics"
+	}
+	product := cfg.Product
+	if product == "" {
+		product = "applogger"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|%s|%s|%s|%s|%s|%d|rt=%d",
+		cefHeaderEscaper.Replace(vendor),
+		cefHeaderEscaper.Replace(product),
+		cefHeaderEscaper.Replace(cfg.Version),
+		cefSignature(e.Level),
+		cefHeaderEscaper.Replace(e.Message),
+		cefSeverity(e.Level),
+		e.Timestamp.UnixNano()/1e6,
+	)
+
+	for _, f := range e.Fields {
+		b.WriteString(" ")
+		b.WriteString(cefExtensionEscaper.Replace(f.Key))
+		b.WriteString("=")
+		b.WriteString(cefExtensionEscaper.Replace(cefValue(f.Value)))
+	}
+	return b.String()
+}
+
+// cefValue formats a field value for the extension
+func cefValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,70 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCEFEvent(t *testing.T) {
+	entry := &LogEntry{
+		Level:     LevelWarn,
+		Timestamp: time.Unix(1700000000, 0),
+		Message:   "disk | almost full",
+		Fields: []Field{
+			{Key: "src", Value: "10.0.0.1"},
+			{Key: "query", Value: "a=b\nc"},
+			{Key: "count", Value: 3},
+		},
+	}
+
+	got := cefEvent(entry, CEFConfig{Product: "billing", Version: "1.2"})
+	want := `CEF:0|codingmechanics|billing|1.2|WARNING|disk \| almost full|6|rt=1700000000000 src=10.0.0.1 query=a\=b\nc count=3`
+	if got != want {
+		t.Errorf("cefEvent:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestCEFSeverity(t *testing.T) {
+	tests := []struct {
+		level     int32
+		severity  int
+		signature string
+	}{
+		{LevelTrace, 0, "TRACE"},
+		{LevelDebug, 1, "DEBUG"},
+		{LevelInfo, 3, "INFO"},
+		{LevelWarn, 6, "WARNING"},
+		{LevelError, 8, "ERROR"},
+		{LevelFatal, 10, "FATAL"},
+	}
+
+	for _, tt := range tests {
+		if got := cefSeverity(tt.level); got != tt.severity {
+			t.Errorf("cefSeverity(%d) = %d, want %d", tt.level, got, tt.severity)
+		}
+		if got := cefSignature(tt.level); got != tt.signature {
+			t.Errorf("cefSignature(%d) = %s, want %s", tt.level, got, tt.signature)
+		}
+	}
+}
+
+func TestFormatCEF(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatCEF, CEF: CEFConfig{Vendor: "acme"}}
+	l.StartWriter(LevelInfo, &buf)
+
+	l.Info("Charge : Completed [%s]", "order=1")
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if !strings.HasPrefix(line, "CEF:0|acme|applogger||INFO|Charge : Completed [order=1]|3|rt=") {
+		t.Errorf("unexpected CEF line %q", line)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("expected a single line, got %q", buf.String())
+	}
+}
@@ -0,0 +1,114 @@
+package applogger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// entries returns the names in dir, sorted
+func entries(t *testing.T, dir string) []string {
+	t.Helper()
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestLogDirectoryCleanupByCount(t *testing.T) {
+	base := tempDir(t)
+	writeLogs(t, base, 0, 1, 2, 5, 30)
+	if err := os.Mkdir(filepath.Join(base, "keep"), 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	l := &Logger{FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &bytes.Buffer{})
+
+	l.LogDirectoryCleanupByCount(base, 0)
+	if got := entries(t, base); len(got) != 6 {
+		t.Errorf("a MaxDirs of 0 removed directories: %v", got)
+	}
+
+	l.LogDirectoryCleanupByCount(base, 2)
+	want := []string{dayDir(1), dayDir(0), "keep"}
+	got := entries(t, base)
+	if len(got) != len(want) {
+		t.Fatalf("left %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("left %v, want %v", got, want)
+		}
+	}
+
+	l.LogDirectoryCleanupByCount(base, 5)
+	if got := entries(t, base); len(got) != 3 {
+		t.Errorf("fewer directories than MaxDirs were removed: %v", got)
+	}
+}
+
+func TestDryRunCleanup(t *testing.T) {
+	base := tempDir(t)
+	writeLogs(t, base, 0, 1, 3, 10)
+	if err := os.Mkdir(filepath.Join(base, "keep"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	for name, days := range map[string]int{"old.log": 5, "new.log": 0, "old.conf": 5} {
+		path := filepath.Join(base, name)
+		if err := ioutil.WriteFile(path, []byte("line\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().AddDate(0, 0, -days)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := entries(t, base)
+
+	l := &Logger{FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &bytes.Buffer{})
+
+	var cleaned []string
+	l.OnCleanup = func(path, reason string) error {
+		cleaned = append(cleaned, path)
+		return nil
+	}
+
+	paths, err := l.DryRunCleanup(base, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{base + "/" + dayDir(10), base + "/" + dayDir(3), base + "/old.log"}
+	if len(paths) != len(want) {
+		t.Fatalf("paths %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("paths %v, want %v", paths, want)
+		}
+	}
+
+	after := entries(t, base)
+	if len(after) != len(before) || len(cleaned) != 0 {
+		t.Errorf("the dry run changed %v to %v, called OnCleanup for %v", before, after, cleaned)
+	}
+}
+
+func TestDryRunCleanupMissingDir(t *testing.T) {
+	l := &Logger{}
+	if _, err := l.DryRunCleanup(filepath.Join(tempDir(t), "missing"), 1); err == nil {
+		t.Error("no error for a missing directory")
+	}
+}
@@ -0,0 +1,73 @@
+// Command logarchiver moves the log directories older than the days to keep
+// out of the log directory, e.g. from cron or a Kubernetes CronJob.
+//
+// Usage:
+//
+//	logarchiver --base-path /var/log/app --archive-path /mnt/archive/app --days-to-keep 7 [--compress] [--dry-run]
+//
+// It archives the same date directories and log files LogDirectoryCleanup
+// would remove. The exit status is 0 when everything was archived, 1 when
+// some paths failed, their errors written to stderr, and 2 for bad flags.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/codingmechanics/applogger"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run archives as the args say and returns the exit status
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("logarchiver", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	basePath := fs.String("base-path", "", "`directory` holding the date directories of the logs")
+	archivePath := fs.String("archive-path", "", "`directory` the old logs are moved to")
+	daysToKeep := fs.Int("days-to-keep", -1, "`days` of logs left in the base path")
+	compress := fs.Bool("compress", false, "gzip the archived log files")
+	dryRun := fs.Bool("dry-run", false, "print the paths that would be archived without moving them")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *basePath == "" || (*archivePath == "" && !*dryRun) || *daysToKeep < 0 {
+		fmt.Fprintln(stderr, "logarchiver: --base-path, --archive-path and --days-to-keep are required")
+		fs.Usage()
+		return 2
+	}
+
+	l := applogger.Discard()
+
+	if *dryRun {
+		paths, err := l.DryRunCleanup(*basePath, *daysToKeep)
+		if err != nil {
+			fmt.Fprintf(stderr, "logarchiver: %s\n", err)
+			return 1
+		}
+		for _, path := range paths {
+			fmt.Fprintln(stdout, path)
+		}
+		return 0
+	}
+
+	err := l.LogDirectoryArchive(*basePath, *archivePath, *daysToKeep, *compress)
+	if err == nil {
+		return 0
+	}
+
+	if archiveErr, ok := err.(*applogger.ArchiveError); ok {
+		for _, err := range archiveErr.Errors {
+			fmt.Fprintf(stderr, "logarchiver: %s\n", err)
+		}
+	} else {
+		fmt.Fprintf(stderr, "logarchiver: %s\n", err)
+	}
+	return 1
+}
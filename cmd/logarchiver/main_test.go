@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	base, err := ioutil.TempDir("", "logarchiver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+	archive := filepath.Join(base, "archive")
+
+	old := time.Now().AddDate(0, 0, -10).Format("2006-01-02")
+	if err := os.MkdirAll(filepath.Join(base, "logs", old), 0777); err != nil {
+		t.Fatal(err)
+	}
+	logs := filepath.Join(base, "logs")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--base-path", logs, "--days-to-keep", "5", "--dry-run"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("dry run exited %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), old) {
+		t.Errorf("dry run output %q misses %s", stdout.String(), old)
+	}
+	if _, err := os.Stat(filepath.Join(logs, old)); err != nil {
+		t.Errorf("the dry run moved %s", old)
+	}
+
+	if code := run([]string{"--base-path", logs, "--archive-path", archive, "--days-to-keep", "5"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("archive exited %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(archive, old)); err != nil {
+		t.Errorf("%s was not archived: %s", old, err)
+	}
+
+	if code := run([]string{"--base-path", filepath.Join(base, "missing"), "--archive-path", archive, "--days-to-keep", "5"}, &stdout, &stderr); code != 1 {
+		t.Errorf("archiving a missing base path exited %d, want 1", code)
+	}
+	if code := run([]string{"--base-path", logs}, &stdout, &stderr); code != 2 {
+		t.Errorf("missing flags exited %d, want 2", code)
+	}
+}
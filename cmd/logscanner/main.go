@@ -0,0 +1,174 @@
+// Command logscanner filters JSONL log files.
+//
+// Usage:
+//
+//	logscanner [flags] [file ...]
+//
+// The files are read in order, standard input when none is given. The
+// entries matching every filter are written as colored text lines, or
+// unchanged as JSONL with --output json.
+//
+//	logscanner --level warn --since 1h --caller orders.go app.log
+//	logscanner --message-contains timeout --output json app.log | jq .
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codingmechanics/applogger/reader"
+)
+
+// level colors, the same as the console of applogger
+const (
+	colorBlack    = 30
+	colorRed      = 31
+	colorGreen    = 32
+	colorYellow   = 33
+	colorBlue     = 34
+	colorDarkGray = 90
+)
+
+func main() {
+	var (
+		filter  reader.Filter
+		since   string
+		until   string
+		output  string
+		noColor bool
+	)
+	flag.StringVar(&filter.Level, "level", "", "least severe `level` written, e.g. warn")
+	flag.StringVar(&since, "since", "", "skip entries before `time`, RFC 3339 or a duration ago, e.g. 1h")
+	flag.StringVar(&until, "until", "", "skip entries from `time` on, RFC 3339 or a duration ago")
+	flag.StringVar(&filter.Caller, "caller", "", "keep entries whose caller file:line or function contains `text`")
+	flag.StringVar(&filter.MessageContains, "message-contains", "", "keep entries whose message contains `text`")
+	flag.StringVar(&output, "output", "text", "output `format`, text or json")
+	flag.BoolVar(&noColor, "no-color", false, "write the text output without colors")
+	flag.Parse()
+
+	if filter.Level != "" {
+		if _, ok := reader.Severity(filter.Level); !ok {
+			fatalf("unknown level %q", filter.Level)
+		}
+	}
+	if output != "text" && output != "json" {
+		fatalf("unknown output %q, want text or json", output)
+	}
+
+	now := time.Now()
+	var err error
+	if filter.Since, err = parseTime(since, now); err != nil {
+		fatalf("--since: %s", err)
+	}
+	if filter.Until, err = parseTime(until, now); err != nil {
+		fatalf("--until: %s", err)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	write := func(e *reader.Entry) {
+		if output == "json" {
+			w.Write(e.Line)
+			w.WriteByte('\n')
+			return
+		}
+		writeText(w, e, !noColor)
+	}
+
+	if flag.NArg() == 0 {
+		if err := scan(os.Stdin, &filter, write); err != nil {
+			w.Flush()
+			fatalf("stdin: %s", err)
+		}
+		return
+	}
+
+	for _, path := range flag.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			w.Flush()
+			fatalf("%s", err)
+		}
+		err = scan(f, &filter, write)
+		f.Close()
+		if err != nil {
+			w.Flush()
+			fatalf("%s: %s", path, err)
+		}
+	}
+}
+
+// scan passes the entries of r matching filter to write
+func scan(r io.Reader, filter *reader.Filter, write func(*reader.Entry)) error {
+	entries := reader.NewJSONLReader(r)
+	for {
+		e, err := entries.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if filter.Match(e) {
+			write(e)
+		}
+	}
+}
+
+// parseTime parses an RFC 3339 time, or a duration subtracted from now
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 time nor a duration", s)
+	}
+	return now.Add(-d), nil
+}
+
+// writeText writes e like the text lines of applogger, the level colored
+func writeText(w io.Writer, e *reader.Entry, color bool) {
+	level := strings.ToUpper(e.Level) + ":"
+	if color {
+		level = fmt.Sprintf("\x1b[%dm%s\x1b[0m", levelColor(e.Level), level)
+	}
+
+	fmt.Fprintf(w, "%s %s %s: %s", level, e.Timestamp.Format("2006/01/02 15:04:05"), e.Caller, e.Message)
+	for _, k := range e.FieldKeys() {
+		fmt.Fprintf(w, " %s=%v", k, e.Fields[k])
+	}
+	io.WriteString(w, "\n")
+}
+
+// levelColor returns the console color of a level name
+func levelColor(level string) int {
+	switch strings.ToLower(level) {
+	case "trace":
+		return colorDarkGray
+	case "debug", "verbose":
+		return colorBlack
+	case "info":
+		return colorBlue
+	case "warn", "warning":
+		return colorYellow
+	case "error", "critical", "fatal":
+		return colorRed
+	}
+	return colorGreen
+}
+
+func fatalf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, "logscanner: "+format+"\n", a...)
+	os.Exit(2)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/codingmechanics/applogger/reader"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		{"2026-01-01T10:00:00Z", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"90m", now.Add(-90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		got, err := parseTime(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseTime(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseTime("yesterday", now); err == nil {
+		t.Error("parseTime accepted yesterday")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	e := &reader.Entry{
+		Level:     "warning",
+		Timestamp: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		Caller:    "orders.go:42",
+		Message:   "Checkout : Payment retried",
+		Fields:    map[string]interface{}{"order": "A1", "attempt": 2},
+	}
+
+	var buf bytes.Buffer
+	writeText(&buf, e, false)
+	want := "WARNING: 2026/01/02 15:04:05 orders.go:42: Checkout : Payment retried attempt=2 order=A1\n"
+	if buf.String() != want {
+		t.Errorf("writeText = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	writeText(&buf, e, true)
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\x1b[33mWARNING:\x1b[0m ")) {
+		t.Errorf("colored writeText = %q", buf.String())
+	}
+}
@@ -0,0 +1,102 @@
+package applogger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CompressFile gzips path into path.gz and removes path once the
+// compressed file is written
+func CompressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(path)
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if serr := dst.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
+
+// compressDirectory gzips the log files of dir that are not open for writing
+func (l *Logger) compressDirectory(dir string) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		l.ErrorWith(err, "LogDirectoryCleanup : Attempting To Read Directory [%s] :", dir)
+		return
+	}
+
+	ext := "." + strings.TrimPrefix(l.FileExtension, ".")
+	if ext == "." {
+		ext = ".txt"
+	}
+
+	open := l.openFiles()
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() || filepath.Ext(fileInfo.Name()) != ext {
+			continue
+		}
+
+		fullFileName := fmt.Sprintf("%s/%s", dir, fileInfo.Name())
+		if open[absPath(fullFileName)] {
+			continue
+		}
+
+		l.Debug("LogDirectoryCleanup : Compressing File[%s]", fullFileName)
+		if err := CompressFile(fullFileName); err != nil {
+			l.ErrorWith(err, "LogDirectoryCleanup : Failed to Compress File [%s] :", fullFileName)
+		}
+	}
+}
+
+// openFiles returns the absolute paths of the log files being written
+func (l *Logger) openFiles() map[string]bool {
+	a := l.instance()
+
+	a.mu.RLock()
+	files := append([]*os.File{a.LogFile}, a.levelFiles...)
+	a.mu.RUnlock()
+
+	open := make(map[string]bool, len(files))
+	for _, f := range files {
+		if f != nil {
+			open[absPath(f.Name())] = true
+		}
+	}
+	return open
+}
+
+// absPath returns path made absolute, or cleaned when it cannot be resolved
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
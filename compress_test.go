@@ -0,0 +1,89 @@
+package applogger
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// gunzip returns the decompressed content of the gzip file at path
+func gunzip(t *testing.T, path string) string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCompressFile(t *testing.T) {
+	path := filepath.Join(tempDir(t), "app.txt")
+	const content = "INFO: 2024/01/15 10:00:00 main.go:10: started\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CompressFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("the original file was kept")
+	}
+	if got := gunzip(t, path+".gz"); got != content {
+		t.Errorf("decompressed %q, want %q", got, content)
+	}
+}
+
+func TestCompressFileMissing(t *testing.T) {
+	path := filepath.Join(tempDir(t), "missing.txt")
+	if err := CompressFile(path); err == nil {
+		t.Error("CompressFile of a missing file returned no error")
+	}
+	if _, err := os.Stat(path + ".gz"); !os.IsNotExist(err) {
+		t.Error("a .gz file was created")
+	}
+}
+
+func TestCompressOldFiles(t *testing.T) {
+	base := tempDir(t)
+	writeLogs(t, base, 1, 2)
+
+	l := &Logger{FileLogLevel: LevelInfo, CompressOldFiles: true}
+	a, err := l.TryStartFile(LevelError, base, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	for _, d := range []int{1, 2} {
+		dir := filepath.Join(base, dayDir(d))
+		if got := entries(t, dir); len(got) != 1 || got[0] != "app.txt.gz" {
+			t.Errorf("%s holds %v, want app.txt.gz", dir, got)
+			continue
+		}
+		if got := gunzip(t, filepath.Join(dir, "app.txt.gz")); got != "day "+dayDir(d)+"\n" {
+			t.Errorf("decompressed %q", got)
+		}
+	}
+
+	// The file being written is left alone
+	if _, err := os.Stat(a.LogFile.Name()); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(a.LogFile.Name() + ".gz"); !os.IsNotExist(err) {
+		t.Error("the open log file was compressed")
+	}
+}
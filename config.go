@@ -0,0 +1,108 @@
+package applogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// configWatchInterval is how often WatchConfigFile checks the file
+var configWatchInterval = time.Second
+
+// Config holds the settings that can be changed while the logger runs, e.g.
+//
+//	{"level": "debug|warn", "auto_sample": 100, "sample_at": {"user.go:142": 0.1}}
+//
+// Settings left out are not changed.
+type Config struct {
+	// Level is set with SetLevel, level names joined by "|" like ParseLevelMask reads
+	Level string `json:"level,omitempty"`
+	// AutoSample is set with AutoSample when present, 0 turns it off
+	AutoSample *float64 `json:"auto_sample,omitempty"`
+	// SampleAt are the call sites set with SampleAt and their rate
+	SampleAt map[string]float64 `json:"sample_at,omitempty"`
+}
+
+// ApplyConfig sets the settings of cfg. Nothing is changed when the level is
+// not valid.
+func (l *Logger) ApplyConfig(cfg Config) error {
+	var level int32
+	if cfg.Level != "" {
+		var err error
+		if level, err = ParseLevelMask(cfg.Level); err != nil {
+			return err
+		}
+	}
+
+	if level != 0 {
+		if err := l.SetLevel(level); err != nil {
+			return err
+		}
+	}
+	if cfg.AutoSample != nil {
+		l.AutoSample(*cfg.AutoSample)
+	}
+	for callerKey, rate := range cfg.SampleAt {
+		l.SampleAt(callerKey, rate)
+	}
+	return nil
+}
+
+// LoadConfigFile reads the JSON Config at path and applies it
+func (l *Logger) LoadConfigFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return fmt.Errorf("applogger: config %s: %s", path, err)
+	}
+	return l.ApplyConfig(cfg)
+}
+
+// WatchConfigFile applies the JSON Config at path with LoadConfigFile every time
+// the file is written, until Stop is called. The file is checked every second.
+// Creating or removing the file does not reload it. A config that fails to load
+// is reported to stderr and the settings in use are kept. It returns the error
+// of the file when it cannot be read at the start.
+func (l *Logger) WatchConfigFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	l.pending().background(func(stopped <-chan struct{}) {
+		ticker := time.NewTicker(configWatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopped:
+				return
+			case <-ticker.C:
+			}
+
+			current, err := os.Stat(path)
+			if err != nil {
+				// Removed, a file created at path later is not a write
+				info = nil
+				continue
+			}
+
+			written := info != nil && (!current.ModTime().Equal(info.ModTime()) || current.Size() != info.Size())
+			info = current
+			if !written {
+				continue
+			}
+
+			if err := l.LoadConfigFile(path); err != nil {
+				fmt.Fprintf(os.Stderr, "applogger: WatchConfigFile: %s\n", err)
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,103 @@
+package applogger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApplyConfig(t *testing.T) {
+	l := &Logger{}
+	l.StartWriter(LevelInfo, &bytes.Buffer{})
+
+	rate := 5.0
+	if err := l.ApplyConfig(Config{Level: "debug", AutoSample: &rate, SampleAt: map[string]float64{"user.go:142": 0.5}}); err != nil {
+		t.Fatal(err)
+	}
+	a := l.instance()
+	if a.LogLevel() != LevelDebug {
+		t.Errorf("level %d, want %d", a.LogLevel(), LevelDebug)
+	}
+	if _, ok := a.samplers.Load(sampleKey("user.go:142")); !ok {
+		t.Error("SampleAt was not set")
+	}
+
+	if err := l.ApplyConfig(Config{Level: "loud"}); err == nil {
+		t.Error("ApplyConfig accepted an unknown level")
+	}
+	if a.LogLevel() != LevelDebug {
+		t.Error("a failed ApplyConfig changed the level")
+	}
+}
+
+// waitLevel waits for the level of a to become level
+func waitLevel(a *ApplicationLog, level int32) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if a.LogLevel() == level {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	interval := configWatchInterval
+	configWatchInterval = time.Millisecond
+	defer func() { configWatchInterval = interval }()
+
+	path := filepath.Join(tempDir(t), "log.json")
+	write := func(content string, age time.Duration) {
+		t.Helper()
+		if err := ioutil.WriteFile(path, []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+		// The time changes on every write, also on coarse file systems
+		mtime := time.Now().Add(age)
+		os.Chtimes(path, mtime, mtime)
+	}
+	write(`{"level":"info"}`, -time.Hour)
+
+	l := &Logger{}
+	a := l.StartWriter(LevelInfo, &bytes.Buffer{})
+	if err := l.WatchConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	write(`{"level":"debug"}`, -time.Minute)
+	if !waitLevel(a, LevelDebug) {
+		t.Fatalf("level %d after the write, want %d", a.LogLevel(), LevelDebug)
+	}
+
+	// An invalid config keeps the level
+	write(`{"level":`, -time.Second)
+	time.Sleep(20 * time.Millisecond)
+	if a.LogLevel() != LevelDebug {
+		t.Errorf("level %d after an invalid config", a.LogLevel())
+	}
+
+	// Removing and creating the file are not writes
+	os.Remove(path)
+	time.Sleep(20 * time.Millisecond)
+	write(`{"level":"error"}`, 0)
+	time.Sleep(20 * time.Millisecond)
+	if a.LogLevel() != LevelDebug {
+		t.Errorf("level %d after the file was created again", a.LogLevel())
+	}
+
+	write(`{"level":"warn"}`, time.Minute)
+	if !waitLevel(a, LevelWarn) {
+		t.Errorf("level %d after writing the new file, want %d", a.LogLevel(), LevelWarn)
+	}
+}
+
+func TestWatchConfigFileMissing(t *testing.T) {
+	if err := Discard().WatchConfigFile(filepath.Join(tempDir(t), "missing.json")); err == nil {
+		t.Error("WatchConfigFile of a missing file returned no error")
+	}
+}
@@ -0,0 +1,98 @@
+package applogger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// contextKey is the type of the context keys read by the Ctx methods
+type contextKey string
+
+// context keys read by the Ctx methods, e.g.
+// context.WithValue(ctx, applogger.ContextKeyTraceID, span.TraceID)
+const (
+	ContextKeyRequestID contextKey = "request_id"
+	ContextKeyTraceID   contextKey = "trace_id"
+	ContextKeySpanID    contextKey = "span_id"
+)
+
+// loggerContextKey holds the logger of NewContextLogger
+const loggerContextKey contextKey = "logger"
+
+// contextPrefixes are the keys written by the Ctx methods, in order, with their name in the line
+var contextPrefixes = []struct {
+	key  contextKey
+	name string
+}{
+	{ContextKeyRequestID, "req"},
+	{ContextKeyTraceID, "trace"},
+	{ContextKeySpanID, "span"},
+}
+
+// WithRequestID returns a copy of ctx carrying the request id written by the Ctx methods
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ContextKeyRequestID, id)
+}
+
+// NewContextLogger returns a copy of l writing the ids of ctx read by the Ctx
+// methods with every line like WithFields, e.g. req=abc123 trace=def456 message.
+// The ids are read once, so a request handler can log many lines without
+// looking them up again. WithContextLogger also stores it in ctx.
+func NewContextLogger(ctx context.Context, l *Logger) *Logger {
+	fields := make(map[string]string)
+	if ctx != nil {
+		for _, p := range contextPrefixes {
+			if v := ctx.Value(p.key); v != nil && v != "" {
+				fields[p.name] = fmt.Sprint(v)
+			}
+		}
+	}
+	return l.WithFields(fields)
+}
+
+// WithContextLogger returns a copy of ctx carrying the logger of NewContextLogger,
+// so the handlers down the call chain get it back with LoggerFromContext
+func WithContextLogger(ctx context.Context, l *Logger) (context.Context, *Logger) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cl := NewContextLogger(ctx, l)
+	return ContextWithLogger(ctx, cl), cl
+}
+
+// ContextWithLogger returns a copy of ctx carrying l for LoggerFromContext
+func ContextWithLogger(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// LoggerFromContext returns the logger of ContextWithLogger, or the logger that
+// started the default ApplicationLog when ctx carries none
+func LoggerFromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerContextKey).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return Default().config
+}
+
+// contextMessage prepends the ids found in ctx to the formatted message,
+// e.g. [req=abc123 trace=def456] message
+func contextMessage(ctx context.Context, format string, a ...interface{}) string {
+	msg := fmt.Sprintf(format, a...)
+	if ctx == nil {
+		return msg
+	}
+
+	var ids []string
+	for _, p := range contextPrefixes {
+		if v := ctx.Value(p.key); v != nil && v != "" {
+			ids = append(ids, fmt.Sprintf("%s=%v", p.name, v))
+		}
+	}
+	if len(ids) == 0 {
+		return msg
+	}
+	return "[" + strings.Join(ids, " ") + "] " + msg
+}
@@ -0,0 +1,108 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewContextLogger(t *testing.T) {
+	var buf syncBuffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelInfo, &buf)
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	ctx = context.WithValue(ctx, ContextKeyTraceID, "def456")
+
+	ctx, cl := WithContextLogger(ctx, l)
+	if LoggerFromContext(ctx) != cl {
+		t.Fatal("LoggerFromContext does not return the logger stored by WithContextLogger")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			LoggerFromContext(ctx).Info("Handle : Completed")
+		}()
+	}
+	wg.Wait()
+
+	out := buf.String()
+	if n := strings.Count(out, ": req=abc123 trace=def456 Handle : Completed\n"); n != 4 {
+		t.Errorf("%d lines carry the context ids:\n%s", n, out)
+	}
+
+	// l keeps its own fields
+	buf2 := &bytes.Buffer{}
+	l.SetOutput(LevelInfo, buf2)
+	l.Info("Plain : Completed")
+	if strings.Contains(buf2.String(), "req=") {
+		t.Errorf("the ids leaked into l: %s", buf2.String())
+	}
+}
+
+func TestLoggerFromContextDefault(t *testing.T) {
+	if l := LoggerFromContext(context.Background()); l == nil {
+		t.Error("LoggerFromContext without a logger returned nil")
+	}
+	if l := LoggerFromContext(nil); l == nil {
+		t.Error("LoggerFromContext(nil) returned nil")
+	}
+}
+
+func TestContextMessage(t *testing.T) {
+	ctx := context.Background()
+	one := WithRequestID(ctx, "abc123")
+	many := context.WithValue(context.WithValue(one, ContextKeyTraceID, "def456"), ContextKeySpanID, "0123")
+	empty := WithRequestID(ctx, "")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"nil", nil, "Load : Completed 3"},
+		{"none", ctx, "Load : Completed 3"},
+		{"empty", empty, "Load : Completed 3"},
+		{"one", one, "[req=abc123] Load : Completed 3"},
+		{"many", many, "[req=abc123 trace=def456 span=0123] Load : Completed 3"},
+	}
+	for _, tt := range tests {
+		if got := contextMessage(tt.ctx, "Load : Completed %d", 3); got != tt.want {
+			t.Errorf("%s: contextMessage = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCtxMethods(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelDebug, &buf)
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	l.DebugCtx(ctx, "debug")
+	l.InfoCtx(ctx, "info")
+	l.WarningCtx(ctx, "warning")
+	l.ErrorCtx(ctx, "error")
+	l.InfoCtx(context.Background(), "no ids")
+
+	out := buf.String()
+	for _, want := range []string{
+		"DEBUG: ", "[req=abc123] debug",
+		"INFO: ", "[req=abc123] info",
+		"WARNING: ", "[req=abc123] warning",
+		"ERROR: ", "[req=abc123] error",
+		": no ids\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q missing in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package applogger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DBLogLevel is the level of a database driver log call, with the values of
+// pgx.LogLevel and tracelog.LogLevel
+type DBLogLevel int
+
+// The levels of the database drivers
+const (
+	DBLogLevelNone  DBLogLevel = 1
+	DBLogLevelError DBLogLevel = 2
+	DBLogLevelWarn  DBLogLevel = 3
+	DBLogLevelInfo  DBLogLevel = 4
+	DBLogLevelDebug DBLogLevel = 5
+	DBLogLevelTrace DBLogLevel = 6
+)
+
+// DBLogger writes the log calls of a database driver, e.g. pgx, to a logger.
+// The levels of pgx convert to DBLogLevel, so a pgx logger is a one line
+// wrapper:
+//
+//	db := applogger.NewDBLogger(l)
+//	cfg.Logger = pgx.LoggerFunc(func(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+//		db.Log(ctx, applogger.DBLogLevel(level), msg, data)
+//	})
+type DBLogger struct {
+	l *Logger
+}
+
+// NewDBLogger returns a DBLogger writing to l
+func NewDBLogger(l *Logger) *DBLogger {
+	return &DBLogger{l: l}
+}
+
+// Log writes msg at the level matching level with data as fields sorted by
+// key. The request, trace and span ids of ctx prefix the message.
+// DBLogLevelNone writes nothing.
+func (d *DBLogger) Log(ctx context.Context, level DBLogLevel, msg string, data map[string]interface{}) {
+	if level == DBLogLevelNone {
+		return
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, Field{Key: k, Value: data[k]})
+	}
+
+	d.l.write(dbLevel(level), 2, contextMessage(ctx, "%s", msg), fields...)
+}
+
+// dbLevel maps the level of a driver to the level written, unknown levels are errors
+func dbLevel(level DBLogLevel) int32 {
+	switch level {
+	case DBLogLevelTrace:
+		return LevelTrace
+	case DBLogLevelDebug:
+		return LevelDebug
+	case DBLogLevelInfo:
+		return LevelInfo
+	case DBLogLevelWarn:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+// SQLLogger writes the messages of database/sql drivers logging through a
+// Print(v ...interface{}) method, e.g. mysql.SetLogger of go-sql-driver/mysql.
+// The drivers report errors through it, the messages are written at Level.
+type SQLLogger struct {
+	// Level is the level the messages are written at, LevelError by default
+	Level int32
+
+	l *Logger
+}
+
+// NewSQLLogger returns a SQLLogger writing errors to l
+func NewSQLLogger(l *Logger) *SQLLogger {
+	return &SQLLogger{Level: LevelError, l: l}
+}
+
+// Print writes the operands like fmt.Print
+func (s *SQLLogger) Print(v ...interface{}) {
+	level := s.Level
+	if level == 0 {
+		level = LevelError
+	}
+	s.l.write(level, 2, fmt.Sprint(v...))
+}
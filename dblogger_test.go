@@ -0,0 +1,69 @@
+package applogger
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDBLogger(t *testing.T) {
+	dir := tempDir(t)
+	l := &Logger{DisableColor: true}
+	l.StartFile(LevelDebug, dir, 1)
+	defer l.Stop()
+
+	db := NewDBLogger(l)
+	ctx := WithRequestID(context.Background(), "abc123")
+	db.Log(ctx, DBLogLevelInfo, "Query", map[string]interface{}{"sql": "select 1", "args": []interface{}{}, "rows": 1})
+	db.Log(ctx, DBLogLevelTrace, "hidden trace", nil)
+	db.Log(ctx, DBLogLevelNone, "hidden none", nil)
+	db.Log(context.Background(), DBLogLevelError, "Exec", map[string]interface{}{"err": errors.New("timeout")})
+
+	out := readLogFile(t, dir)
+	if !strings.Contains(out, "INFO: ") || !strings.Contains(out, "dblogger_test.go:") ||
+		!strings.Contains(out, "[req=abc123] Query args=[] rows=1 sql=select 1") {
+		t.Errorf("unexpected query line:\n%s", out)
+	}
+	if !strings.Contains(out, "ERROR: ") || !strings.Contains(out, "Exec err=timeout") {
+		t.Errorf("unexpected error line:\n%s", out)
+	}
+	if strings.Contains(out, "hidden") {
+		t.Errorf("trace or none level written:\n%s", out)
+	}
+}
+
+func TestDBLevel(t *testing.T) {
+	for level, want := range map[DBLogLevel]int32{
+		DBLogLevelTrace: LevelTrace,
+		DBLogLevelDebug: LevelDebug,
+		DBLogLevelInfo:  LevelInfo,
+		DBLogLevelWarn:  LevelWarn,
+		DBLogLevelError: LevelError,
+		DBLogLevel(42):  LevelError,
+	} {
+		if got := dbLevel(level); got != want {
+			t.Errorf("dbLevel(%d) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestSQLLogger(t *testing.T) {
+	dir := tempDir(t)
+	l := &Logger{DisableColor: true}
+	l.StartFile(LevelInfo, dir, 1)
+	defer l.Stop()
+
+	sl := NewSQLLogger(l)
+	sl.Print("packets.go:36: ", "unexpected EOF")
+	sl.Level = LevelWarn
+	sl.Print("busy buffer")
+
+	out := readLogFile(t, dir)
+	if !strings.Contains(out, "ERROR: ") || !strings.Contains(out, "dblogger_test.go:") || !strings.Contains(out, "packets.go:36: unexpected EOF") {
+		t.Errorf("unexpected error line:\n%s", out)
+	}
+	if !strings.Contains(out, "WARNING: ") || !strings.Contains(out, "busy buffer") {
+		t.Errorf("unexpected warning line:\n%s", out)
+	}
+}
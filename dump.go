@@ -0,0 +1,73 @@
+package applogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+)
+
+// defaultMaxBodyLogSize is the body size DumpRequest and DumpResponse cut at
+// when MaxBodyLogSize is not set
+const defaultMaxBodyLogSize = 64 * 1024
+
+// dumpRequest returns the wire form of r with the body cut at MaxBodyLogSize
+func (l *Logger) dumpRequest(r *http.Request) (string, error) {
+	dump, err := httputil.DumpRequest(r, false)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := l.dumpBody(&r.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(dump) + body, nil
+}
+
+// dumpResponse returns the wire form of resp with the body cut at MaxBodyLogSize
+func (l *Logger) dumpResponse(resp *http.Response) (string, error) {
+	dump, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := l.dumpBody(&resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(dump) + body, nil
+}
+
+// dumpBody reads the start of body and puts it back so the handler or client
+// still reads the whole body
+func (l *Logger) dumpBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+
+	max := l.MaxBodyLogSize
+	if max <= 0 {
+		max = defaultMaxBodyLogSize
+	}
+
+	// one more byte tells whether the body was cut
+	start, err := ioutil.ReadAll(io.LimitReader(*body, int64(max)+1))
+	*body = readCloser{Reader: io.MultiReader(bytes.NewReader(start), *body), Closer: *body}
+	if err != nil {
+		return "", err
+	}
+
+	if len(start) > max {
+		return fmt.Sprintf("%s... [body cut at %d bytes]", start[:max], max), nil
+	}
+	return string(start), nil
+}
+
+// readCloser reads from Reader and closes Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
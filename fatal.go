@@ -0,0 +1,54 @@
+package applogger
+
+import "os"
+
+// syncer is implemented by file writers that can flush to disk
+type syncer interface {
+	Sync() error
+}
+
+// exit flushes the log files to disk and ends the process, it is called by Fatal
+func (a *ApplicationLog) exit() {
+	a.flush()
+
+	a.mu.RLock()
+	exitFunc := a.exitFunc
+	a.mu.RUnlock()
+
+	if exitFunc == nil {
+		exitFunc = os.Exit
+	}
+	exitFunc(1)
+}
+
+// flush writes the queued Async lines and syncs the log files to disk
+func (a *ApplicationLog) flush() {
+	// Write the queued Async lines
+	a.stopBackground()
+
+	a.mu.RLock()
+	files := append([]*os.File{a.LogFile}, a.levelFiles...)
+	fileWriter := a.fileWriter
+	a.mu.RUnlock()
+
+	for _, f := range files {
+		if f != nil {
+			f.Sync()
+		}
+	}
+	if s, ok := fileWriter.(syncer); ok {
+		s.Sync()
+	}
+}
+
+// panicWith panics with msg, it is called by Panic
+func (a *ApplicationLog) panicWith(msg string) {
+	a.mu.RLock()
+	panicFunc := a.panicFunc
+	a.mu.RUnlock()
+
+	if panicFunc == nil {
+		panic(msg)
+	}
+	panicFunc(msg)
+}
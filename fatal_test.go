@@ -0,0 +1,42 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFatalFlushesThenExits(t *testing.T) {
+	dir := tempDir(t)
+
+	var codes []int
+	var written []string
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo, Async: true}
+	l.ExitFunc = func(c int) {
+		codes = append(codes, c)
+		// The line is in the file when the process would end
+		written = append(written, readFile(t, l.instance().LogFile.Name()))
+	}
+	if _, err := l.TryStartFile(LevelError, filepath.Join(dir, "fatal"), 1); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	l.Info("queued before Fatal")
+	l.Fatal("cannot open %s", "db")
+	l.Fatalf("main", "still %d", 2)
+
+	if len(codes) != 2 || codes[0] != 1 || codes[1] != 1 {
+		t.Fatalf("exit codes %v, want [1 1]", codes)
+	}
+	if !strings.Contains(written[0], "INFO: ") || !strings.Contains(written[0], "queued before Fatal") ||
+		!strings.Contains(written[0], "ERROR: ") || !strings.Contains(written[0], "cannot open db\n") {
+		t.Errorf("log file at the Fatal exit:\n%s", written[0])
+	}
+	if !strings.Contains(written[1], "main() still 2\n") {
+		t.Errorf("log file at the Fatalf exit:\n%s", written[1])
+	}
+}
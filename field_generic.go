@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package applogger
+
+import "time"
+
+// TypedField is a field whose value keeps its type, so formatters like
+// FormatLog4j2JSON write 42 rather than "42"
+type TypedField[T any] struct {
+	Key   string
+	Value T
+}
+
+// KeyValue implements Fields
+func (f TypedField[T]) KeyValue() (string, interface{}) {
+	return f.Key, f.Value
+}
+
+// StringField creates a string field
+func StringField(key, value string) TypedField[string] {
+	return TypedField[string]{Key: key, Value: value}
+}
+
+// IntField creates an int field
+func IntField(key string, value int) TypedField[int] {
+	return TypedField[int]{Key: key, Value: value}
+}
+
+// Int64Field creates an int64 field
+func Int64Field(key string, value int64) TypedField[int64] {
+	return TypedField[int64]{Key: key, Value: value}
+}
+
+// Float64Field creates a float64 field
+func Float64Field(key string, value float64) TypedField[float64] {
+	return TypedField[float64]{Key: key, Value: value}
+}
+
+// BoolField creates a bool field
+func BoolField(key string, value bool) TypedField[bool] {
+	return TypedField[bool]{Key: key, Value: value}
+}
+
+// DurationField creates a time.Duration field
+func DurationField(key string, value time.Duration) TypedField[time.Duration] {
+	return TypedField[time.Duration]{Key: key, Value: value}
+}
@@ -0,0 +1,62 @@
+//go:build go1.18 && !nolog_all
+// +build go1.18,!nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestInfoFieldsKeepTypes(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatJSON}
+	l.StartWriter(LevelInfo, &buf)
+
+	l.InfoFields("Charge : Completed",
+		StringField("order", "a1"),
+		IntField("items", 42),
+		BoolField("paid", true),
+		Field{Key: "currency", Value: "EUR"},
+	)
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+	if line["message"] != "Charge : Completed" {
+		t.Errorf("message = %v", line["message"])
+	}
+	if line["order"] != "a1" || line["items"] != float64(42) || line["paid"] != true || line["currency"] != "EUR" {
+		t.Errorf("fields lost their types: %v", line)
+	}
+}
+
+func TestToFields(t *testing.T) {
+	fields := ToFields(DurationField("latency", time.Second), Int64Field("bytes", 7), Float64Field("ratio", 0.5))
+
+	want := []Field{
+		{Key: "latency", Value: time.Second},
+		{Key: "bytes", Value: int64(7)},
+		{Key: "ratio", Value: 0.5},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("got %d fields, want %d", len(fields), len(want))
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("field %d = %#v, want %#v", i, fields[i], want[i])
+		}
+	}
+}
+
+func TestMockLoggerInfoFields(t *testing.T) {
+	m := &MockLogger{}
+	m.InfoFields("Charge : Completed", IntField("items", 42))
+
+	if !m.WasCalledWith(LevelInfo, "Charge : Completed items=42") {
+		t.Errorf("calls %v", m.Calls)
+	}
+}
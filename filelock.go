@@ -0,0 +1,42 @@
+package applogger
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// EnableFileLock takes an exclusive lock on the log file of StartFile around
+// every write, so processes sharing the file, e.g. pre-fork workers, do not
+// interleave their lines. Every process writing the file has to enable it.
+// It has no effect on the files of StartMultiFile and on SetFileWriter.
+func (l *Logger) EnableFileLock() {
+	atomic.StoreInt32(&l.pending().fileLock, 1)
+}
+
+// lockedFile writes to the log file holding the file lock when it is enabled
+type lockedFile struct {
+	w io.Writer
+	a *ApplicationLog
+}
+
+// Write locks the current log file, writes p and unlocks it
+func (lf *lockedFile) Write(p []byte) (int, error) {
+	if atomic.LoadInt32(&lf.a.fileLock) == 0 {
+		return lf.w.Write(p)
+	}
+
+	lf.a.mu.RLock()
+	f := lf.a.LogFile
+	lf.a.mu.RUnlock()
+
+	if f == nil {
+		return lf.w.Write(p)
+	}
+
+	if err := lockFile(f); err != nil {
+		return 0, err
+	}
+	defer unlockFile(f)
+
+	return lf.w.Write(p)
+}
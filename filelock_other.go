@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package applogger
+
+import "os"
+
+// lockFile is a no-op where file locks are not available
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op where file locks are not available
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,91 @@
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows) && !nolog_all
+// +build darwin dragonfly freebsd linux netbsd openbsd windows
+// +build !nolog_all
+
+package applogger
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnableFileLock(t *testing.T) {
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.EnableFileLock()
+	a, err := l.TryStartFile(LevelError, tempDir(t), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	// Another process holding the lock, the lock is taken per open file
+	other, err := os.OpenFile(a.LogFile.Name(), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := lockFile(other); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		l.Info("waits for the lock")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the line was written while another process held the lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := unlockFile(other); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the line was not written once the lock was released")
+	}
+	if !strings.Contains(readFile(t, a.LogFile.Name()), "waits for the lock\n") {
+		t.Error("the line is missing in the file")
+	}
+}
+
+func BenchmarkFileLock(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		lock bool
+	}{
+		{"Unlocked", false},
+		{"Locked", true},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			dir, err := ioutil.TempDir("", "applogger")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			// Only the file write is measured, the console logs errors only
+			l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+			if bm.lock {
+				l.EnableFileLock()
+			}
+			if _, err := l.TryStartFile(LevelError, dir, 1); err != nil {
+				b.Fatal(err)
+			}
+			defer l.Stop()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info("BenchmarkFileLock : Completed [%d]", i)
+			}
+		})
+	}
+}
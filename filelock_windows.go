@@ -0,0 +1,25 @@
+//go:build windows
+// +build windows
+
+package applogger
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRangeMax locks the whole file, whatever its size
+const lockRangeMax = 0xffffffff
+
+// lockFile takes an exclusive LockFileEx lock on f, waiting for other processes
+func lockFile(f *os.File) error {
+	var overlapped windows.Overlapped
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, lockRangeMax, lockRangeMax, &overlapped)
+}
+
+// unlockFile releases the LockFileEx lock on f
+func unlockFile(f *os.File) error {
+	var overlapped windows.Overlapped
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, lockRangeMax, lockRangeMax, &overlapped)
+}
@@ -0,0 +1,64 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	e := &LogEntry{Level: LevelError, Caller: "orders.go:42", Message: "Checkout : Failed [" + strings.Repeat("x", 300) + "]"}
+	same := &LogEntry{Level: LevelError, Caller: "orders.go:42", Message: e.Message[:200] + "different tail"}
+	other := &LogEntry{Level: LevelWarn, Caller: "orders.go:42", Message: e.Message}
+
+	if e.Fingerprint() != same.Fingerprint() {
+		t.Error("the fingerprint depends on the message past 200 bytes")
+	}
+	if e.Fingerprint() == other.Fingerprint() {
+		t.Error("entries of different levels share the fingerprint")
+	}
+	if len(e.Fingerprint()) != 64 {
+		t.Errorf("fingerprint %q is not a hex sha256", e.Fingerprint())
+	}
+}
+
+func TestLogFingerprint(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatLog4j2JSON} {
+		for _, on := range []bool{false, true} {
+			var buf bytes.Buffer
+			l := &Logger{Format: format, LogFingerprint: on}
+			l.StartWriter(LevelInfo, &buf)
+			l.Info("Checkout : Completed")
+
+			event := make(map[string]interface{})
+			if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+				t.Fatalf("format %v: invalid JSON %q: %s", format, buf.String(), err)
+			}
+			fingerprint, ok := event["fingerprint"].(string)
+			if ok != on {
+				t.Errorf("format %v LogFingerprint %v: fingerprint written = %v:\n%s", format, on, ok, buf.String())
+			}
+			if on && len(fingerprint) != 64 {
+				t.Errorf("format %v: fingerprint %q", format, fingerprint)
+			}
+		}
+	}
+}
+
+func TestLog4j2EventLayout(t *testing.T) {
+	e := &LogEntry{Level: LevelInfo, Message: "Checkout : Completed"}
+	line, err := log4j2Event(e, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The default event only holds the keys of the log4j2 JsonLayout
+	want := `{"instant":{"epochSecond":-62135596800,"nanoOfSecond":0},"thread":"applogger","level":"INFO","loggerName":"applogger","message":"Checkout : Completed","endOfBatch":false,"loggerFqcn":"github.com/codingmechanics/applogger.Logger"}`
+	if string(line) != want {
+		t.Errorf("log4j2Event =\n%s\nwant\n%s", line, want)
+	}
+}
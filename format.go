@@ -0,0 +1,161 @@
+package applogger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format selects how log lines are written
+type Format string
+
+const (
+	// FormatText logs the prefixed plain text lines of the log package
+	FormatText Format = "text"
+
+	// FormatApacheCombined logs GinLogger requests in Apache Combined Log Format
+	FormatApacheCombined Format = "apache-combined"
+
+	// FormatW3CExtended logs GinLogger requests in W3C Extended Log Format
+	FormatW3CExtended Format = "w3c-extended"
+
+	// FormatCEF logs every line as an ArcSight Common Event Format event
+	FormatCEF Format = "cef"
+
+	// FormatLog4j2JSON logs every line as a log4j2 JsonLayout event
+	FormatLog4j2JSON Format = "log4j2-json"
+
+	// FormatLogfmt logs every line as logfmt key=value pairs
+	FormatLogfmt Format = "logfmt"
+
+	// FormatJSON logs every line as a JSON object with the level, timestamp,
+	// caller, function and message followed by the fields
+	FormatJSON Format = "json"
+)
+
+// isJSON reports whether the format writes JSON lines
+func (f Format) isJSON() bool {
+	return f == FormatLog4j2JSON || f == FormatJSON
+}
+
+// isStructured reports whether the fields are written as keys of their own
+// instead of being appended to a text line
+func (f Format) isStructured() bool {
+	return f.isJSON() || f == FormatCEF || f == FormatLogfmt
+}
+
+// Field is a key value pair attached to a LogEntry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Fields is implemented by Field and by the typed fields
+type Fields interface {
+	// KeyValue returns the key and the value with its concrete type
+	KeyValue() (string, interface{})
+}
+
+// KeyValue implements Fields
+func (f Field) KeyValue() (string, interface{}) {
+	return f.Key, f.Value
+}
+
+// ToFields converts typed fields to the []Field stored on a LogEntry
+func ToFields(fields ...Fields) []Field {
+	list := make([]Field, len(fields))
+	for i, f := range fields {
+		list[i].Key, list[i].Value = f.KeyValue()
+	}
+	return list
+}
+
+// LogEntry holds a single log line and the fields it was written with
+type LogEntry struct {
+	Level int32
+	// LevelName is the alias the entry was written with, e.g. VERBOSE, empty otherwise
+	LevelName string
+	Timestamp time.Time
+	// Caller is the file:line the entry was written from, e.g. service.go:142
+	Caller string
+	// Function is the package qualified function the entry was written from
+	Function string
+	Message  string
+	Fields   []Field
+}
+
+// fingerprintMessageLen is how much of the message goes into the fingerprint
+const fingerprintMessageLen = 200
+
+// Fingerprint identifies entries written at the same level and caller with the
+// same message, so aggregators can group the same error reported by many hosts.
+// Only the first 200 bytes of the message are used. FormatJSON and FormatLog4j2JSON
+// write it as fingerprint with LogFingerprint.
+func (e *LogEntry) Fingerprint() string {
+	msg := e.Message
+	if len(msg) > fingerprintMessageLen {
+		msg = msg[:fingerprintMessageLen]
+	}
+
+	sum := sha256.Sum256([]byte(strconv.Itoa(int(e.Level)) + "|" + e.Caller + "|" + msg))
+	return hex.EncodeToString(sum[:])
+}
+
+// Format returns the entry formatted as a single line without writing it.
+// FormatJSON, FormatLog4j2JSON, FormatLogfmt and FormatCEF are supported, the
+// other formats return the entry as a text line without the caller.
+func (e *LogEntry) Format(format Format) string {
+	switch format {
+	case FormatJSON:
+		if line, err := jsonEvent(e, false); err == nil {
+			return string(line)
+		}
+	case FormatCEF:
+		return cefEvent(e, CEFConfig{})
+	case FormatLogfmt:
+		return logfmtEvent(e)
+	case FormatLog4j2JSON:
+		if line, err := log4j2Event(e, false, false); err == nil {
+			return string(line)
+		}
+	}
+	return fmt.Sprintf("%s: %s %s%s", textLevel(e), textTimestamps.format(e.Timestamp), e.Message, textFields(e.Fields))
+}
+
+// textLevel is the level name of the entry used by the text prefixes
+func textLevel(e *LogEntry) string {
+	if e.LevelName != "" {
+		return e.LevelName
+	}
+
+	switch e.Level {
+	case LevelTrace:
+		return "TRACE"
+	case LevelFatal:
+		return "FATAL"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+// textFields formats fields as " key=value" pairs appended to a text line
+func textFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	return b.String()
+}
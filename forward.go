@@ -0,0 +1,59 @@
+package applogger
+
+import "errors"
+
+// ErrForwardCycle is returned by ForwardTo when the parent already forwards to the logger
+var ErrForwardCycle = errors.New("applogger: forwarding cycle")
+
+// ForwardTo writes every entry of the logger to parent as well, at the same
+// level when the level of parent logs it, e.g. a component logger that also
+// feeds the log of its service. The entry keeps the caller it was written
+// from and is formatted by parent. Parents forward to their own parents, a
+// parent forwarding to the logger, directly or not, returns ErrForwardCycle.
+func (l *Logger) ForwardTo(parent *Logger) error {
+	a := l.pending()
+	if parent.instance().forwardsTo(a, map[*ApplicationLog]bool{}) {
+		return ErrForwardCycle
+	}
+
+	a.mu.Lock()
+	a.parents = append(append([]*Logger(nil), a.parents...), parent)
+	a.mu.Unlock()
+	return nil
+}
+
+// forwardsTo reports whether a is target or forwards to it
+func (a *ApplicationLog) forwardsTo(target *ApplicationLog, visited map[*ApplicationLog]bool) bool {
+	if a == target {
+		return true
+	}
+	if visited[a] {
+		return false
+	}
+	visited[a] = true
+
+	a.mu.RLock()
+	parents := a.parents
+	a.mu.RUnlock()
+
+	for _, p := range parents {
+		if p.instance().forwardsTo(target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// forward writes the entry to the parents logging its level
+func (a *ApplicationLog) forward(entry *LogEntry, calldepth int) {
+	a.mu.RLock()
+	parents := a.parents
+	a.mu.RUnlock()
+
+	for _, p := range parents {
+		pa := p.instance()
+		if levelOn(entry.Level, pa.LogLevel()) {
+			pa.emit(entry, calldepth+1)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForwardTo(t *testing.T) {
+	childDir, parentDir := tempDir(t), tempDir(t)
+
+	child := &Logger{DisableColor: true}
+	child.StartFile(LevelDebug, childDir, 1)
+	defer child.Stop()
+
+	parent := &Logger{DisableColor: true}
+	parent.StartFile(LevelWarn, parentDir, 1)
+	defer parent.Stop()
+
+	if err := child.ForwardTo(parent); err != nil {
+		t.Fatal(err)
+	}
+
+	child.Info("not forwarded")
+	child.Warning("forwarded")
+
+	childOut, parentOut := readLogFile(t, childDir), readLogFile(t, parentDir)
+	if !strings.Contains(childOut, "not forwarded") || !strings.Contains(childOut, ": forwarded\n") {
+		t.Errorf("child output:\n%s", childOut)
+	}
+	if strings.Contains(parentOut, "not forwarded") || strings.Count(parentOut, ": forwarded\n") != 1 {
+		t.Errorf("parent output:\n%s", parentOut)
+	}
+	if !strings.Contains(parentOut, "forward_test.go:") {
+		t.Errorf("the forwarded entry lost its caller:\n%s", parentOut)
+	}
+}
+
+func TestForwardToCycle(t *testing.T) {
+	a, b, c := Discard(), Discard(), Discard()
+
+	if err := a.ForwardTo(a); err != ErrForwardCycle {
+		t.Errorf("a.ForwardTo(a) = %v, want ErrForwardCycle", err)
+	}
+	if err := a.ForwardTo(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.ForwardTo(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ForwardTo(a); err != ErrForwardCycle {
+		t.Errorf("c.ForwardTo(a) = %v, want ErrForwardCycle", err)
+	}
+	// a diamond is not a cycle
+	if err := a.ForwardTo(c); err != nil {
+		t.Errorf("a.ForwardTo(c) = %v", err)
+	}
+}
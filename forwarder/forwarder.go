@@ -0,0 +1,154 @@
+// Package forwarder ships the lines appended to a log file to another writer.
+package forwarder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileForwarder tails a log file and writes every new line to a writer.
+// The position in the file is kept in a cursor file next to it, so a
+// restarted forwarder continues where the previous one stopped.
+type FileForwarder struct {
+	filePath     string
+	writer       io.WriteCloser
+	pollInterval time.Duration
+
+	offset    int64
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewFileForwarder starts forwarding the lines appended to filePath to writer,
+// checking the file every pollInterval. Without a cursor file it starts at the
+// current end of the file.
+func NewFileForwarder(filePath string, writer io.WriteCloser, pollInterval time.Duration) *FileForwarder {
+	f := &FileForwarder{
+		filePath:     filePath,
+		writer:       writer,
+		pollInterval: pollInterval,
+		done:         make(chan struct{}),
+	}
+	f.offset = f.initialOffset()
+
+	f.wg.Add(1)
+	go f.run()
+
+	return f
+}
+
+// cursorPath is the file the position is saved in
+func (f *FileForwarder) cursorPath() string {
+	return f.filePath + ".cursor"
+}
+
+// initialOffset reads the saved cursor or falls back to the end of the file
+func (f *FileForwarder) initialOffset() int64 {
+	if b, err := ioutil.ReadFile(f.cursorPath()); err == nil {
+		if offset, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64); err == nil {
+			return offset
+		}
+	}
+
+	if fi, err := os.Stat(f.filePath); err == nil {
+		return fi.Size()
+	}
+	return 0
+}
+
+// run polls the file until Close is called
+func (f *FileForwarder) run() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+			if err := f.poll(); err != nil {
+				fmt.Fprintf(os.Stderr, "forwarder: %s : %s\n", f.filePath, err)
+			}
+		}
+	}
+}
+
+// poll forwards the complete lines written since the last poll. A line
+// still being written is left for the next poll.
+func (f *FileForwarder) poll() error {
+	file, err := os.Open(f.filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// The file was truncated or replaced, start over from the beginning.
+	if fi.Size() < f.offset {
+		f.offset = 0
+	}
+	if fi.Size() == f.offset {
+		return nil
+	}
+
+	if _, err := file.Seek(f.offset, io.SeekStart); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(file, fi.Size()-f.offset))
+	if err != nil {
+		return err
+	}
+
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+
+		if _, err := f.writer.Write(data[:i+1]); err != nil {
+			f.saveCursor()
+			return err
+		}
+		f.offset += int64(i + 1)
+		data = data[i+1:]
+	}
+
+	return f.saveCursor()
+}
+
+// saveCursor writes the current position to the cursor file
+func (f *FileForwarder) saveCursor() error {
+	return ioutil.WriteFile(f.cursorPath(), []byte(strconv.FormatInt(f.offset, 10)), 0644)
+}
+
+// Close stops polling, saves the cursor and closes the writer
+func (f *FileForwarder) Close() error {
+	var err error
+	f.closeOnce.Do(func() {
+		close(f.done)
+		f.wg.Wait()
+
+		err = f.saveCursor()
+		if cerr := f.writer.Close(); err == nil {
+			err = cerr
+		}
+	})
+	return err
+}
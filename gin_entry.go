@@ -0,0 +1,67 @@
+package applogger
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinLogEntry describes a request handled by gin, see GinLoggerWithEntry
+type GinLogEntry struct {
+	Method    string
+	Path      string
+	ClientIP  string
+	Status    int
+	Latency   time.Duration
+	UserAgent string
+	// RequestID is the X-Request-ID header, empty when the request has none
+	RequestID string
+
+	// The details written by the Apache format, copied from the request
+	// since gin reuses its Context once the handlers return
+	start   time.Time
+	uri     string
+	proto   string
+	size    int
+	user    string
+	referer string
+	errors  string
+}
+
+// newGinLogEntry copies the details of the request handled by c, started at start
+func newGinLogEntry(c *gin.Context, start time.Time) GinLogEntry {
+	uri := c.Request.RequestURI
+	if uri == "" {
+		uri = c.Request.URL.RequestURI()
+	}
+
+	return GinLogEntry{
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		ClientIP:  c.ClientIP(),
+		Status:    c.Writer.Status(),
+		Latency:   time.Since(start),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: c.GetHeader("X-Request-ID"),
+		start:     start,
+		uri:       uri,
+		proto:     c.Request.Proto,
+		size:      c.Writer.Size(),
+		user:      c.GetString(gin.AuthUserKey),
+		referer:   c.Request.Referer(),
+		errors:    c.Errors.String(),
+	}
+}
+
+// GinLoggerWithEntry calls fn with every request once it is handled instead
+// of writing a line, e.g. to send the requests to a queue or to collect them
+// in tests. GinLogger writes the entries in the configured format.
+func (l *Logger) GinLoggerWithEntry(fn func(GinLogEntry)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t := time.Now()
+		// process request
+		c.Next()
+
+		fn(newGinLogEntry(c, t))
+	}
+}
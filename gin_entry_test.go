@@ -0,0 +1,213 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGinLoggerWithEntry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var entries []GinLogEntry
+	r := gin.New()
+	r.Use(Discard().GinLoggerWithEntry(func(e GinLogEntry) { entries = append(entries, e) }))
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	req.Header.Set("User-Agent", "tests")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(entries) != 1 {
+		t.Fatalf("%d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Method != http.MethodGet || e.Path != "/users/42" || e.Status != http.StatusTeapot ||
+		e.RequestID != "abc123" || e.UserAgent != "tests" || e.Latency <= 0 {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
+
+func TestGinLogEntryCopiesRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var entries []GinLogEntry
+	r := gin.New()
+	r.Use(Discard().GinLoggerWithEntry(func(e GinLogEntry) { entries = append(entries, e) }))
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Error(errors.New("lookup failed " + c.Param("id")))
+		c.String(http.StatusNotFound, "missing")
+	})
+
+	for _, path := range []string{"/users/1?v=1", "/users/2?v=2"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Referer", "http://example.com"+path)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// gin reuses the Context of the first request for the second one
+	if len(entries) != 2 {
+		t.Fatalf("%d entries, want 2", len(entries))
+	}
+	line := apacheCombined(entries[0], true)
+	if !strings.Contains(line, `"GET /users/1?v=1 HTTP/1.1" 404 7 "http://example.com/users/1?v=1"`) {
+		t.Errorf("the first entry does not describe the first request: %s", line)
+	}
+	if !strings.Contains(entries[0].errors, "lookup failed 1") || !strings.Contains(entries[1].errors, "lookup failed 2") {
+		t.Errorf("errors %q and %q", entries[0].errors, entries[1].errors)
+	}
+}
+
+func TestGinLoggerWithConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelInfo, &buf)
+
+	r := gin.New()
+	r.Use(l.GinLoggerWithConfig(GinLoggerConfig{
+		SkipPaths:    []string{"/healthz"},
+		CustomFields: func(c *gin.Context) map[string]string { return map[string]string{"user": c.Param("id")} },
+	}))
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/healthz", func(c *gin.Context) {})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	out := buf.String()
+	if !strings.Contains(out, "WARNING: ") || !strings.Contains(out, "| 404 |") || !strings.Contains(out, "/users/42") || !strings.Contains(out, " user=42") {
+		t.Errorf("unexpected request line:\n%s", out)
+	}
+	if strings.Contains(out, "/healthz") {
+		t.Errorf("a skipped path was logged:\n%s", out)
+	}
+}
+
+func TestGinLoggerWithConfigApache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatApacheCombined}
+	l.StartWriter(LevelInfo, &buf)
+
+	r := gin.New()
+	r.Use(l.GinLogger())
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !strings.Contains(buf.String(), `"GET / HTTP/1.1" 200 2`) {
+		t.Errorf("unexpected Apache line:\n%s", buf.String())
+	}
+}
+
+func TestGinLoggerSlowRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelInfo, &buf)
+
+	r := gin.New()
+	r.Use(l.GinLoggerWithConfig(GinLoggerConfig{SlowRequestThreshold: 5 * time.Millisecond}))
+	r.GET("/fast", func(c *gin.Context) {})
+	r.GET("/slow", func(c *gin.Context) { time.Sleep(10 * time.Millisecond) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "INFO: ") || !strings.Contains(lines[0], "/fast") {
+		t.Errorf("fast request: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "WARNING: ") || !strings.Contains(lines[1], "/slow") {
+		t.Errorf("slow request: %s", lines[1])
+	}
+}
+
+func TestGinLoggerOutput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logged, out bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelError, &logged)
+
+	r := gin.New()
+	r.Use(l.GinLoggerWithConfig(GinLoggerConfig{Output: &out}))
+	r.GET("/", func(c *gin.Context) {})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if logged.Len() != 0 {
+		t.Errorf("the level loggers received the request:\n%s", logged.String())
+	}
+	if !strings.Contains(out.String(), "| 200 |") {
+		t.Errorf("the request was not written to Output, below the log level:\n%s", out.String())
+	}
+}
+
+func TestGinLoggerTraceContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	for _, tt := range []struct {
+		format Format
+		want   string
+	}{
+		{FormatText, " trace=4bf92f3577b34da6a3ce929d0e0e4736 span=00f067aa0ba902b7"},
+		{FormatJSON, `"trace":"4bf92f3577b34da6a3ce929d0e0e4736","span":"00f067aa0ba902b7"`},
+	} {
+		var buf bytes.Buffer
+		l := &Logger{DisableColor: true, Format: tt.format, FileLogLevel: LevelInfo}
+		l.StartWriter(LevelError, &buf)
+
+		r := gin.New()
+		r.Use(l.GinLoggerWithConfig(GinLoggerConfig{LogTraceContext: true}))
+		r.GET("/", func(c *gin.Context) {})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("traceparent", traceParent)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 || !strings.Contains(lines[0], tt.want) {
+			t.Errorf("format %v: %s missing in:\n%s", tt.format, tt.want, buf.String())
+			continue
+		}
+		if strings.Contains(lines[1], "trace") {
+			t.Errorf("format %v: a request without a trace wrote one: %s", tt.format, lines[1])
+		}
+	}
+}
+
+func TestGinLoggerTraceContextSpan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	var span SpanContext
+	r := gin.New()
+	r.Use(l.GinTracingMiddleware(), l.GinLoggerWithConfig(GinLoggerConfig{LogTraceContext: true}))
+	r.GET("/", func(c *gin.Context) { span, _ = SpanFromContext(c) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if span.SpanID == "" || !strings.Contains(buf.String(), " trace="+span.TraceID+" span="+span.SpanID) {
+		t.Errorf("the span %+v of GinTracingMiddleware is missing in:\n%s", span, buf.String())
+	}
+}
@@ -0,0 +1,172 @@
+package applogger
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLatencyRoutes bounds the routes tracked per window, the rest are counted as otherLatencyRoute
+const maxLatencyRoutes = 1000
+
+// otherLatencyRoute collects the requests of the routes past maxLatencyRoutes
+const otherLatencyRoute = "OTHER"
+
+// GinLatencyConfig selects how often GinLatencyTrackerWithConfig writes its summary
+type GinLatencyConfig struct {
+	// EveryN writes the summary after every n requests, 0 disables it
+	EveryN int
+	// Interval writes the summary every interval until Stop is called, 0 disables it
+	Interval time.Duration
+}
+
+// latencyRoute identifies a route in the latency summary
+type latencyRoute struct {
+	method string
+	route  string
+}
+
+// latencyTracker holds a histogram of the request latencies of the current
+// window per route, its memory is bounded by maxLatencyRoutes
+type latencyTracker struct {
+	mu        sync.Mutex
+	latencies map[latencyRoute]*histogram
+	requests  int
+}
+
+// GinLatencyTracker logs the p50, p95 and p99 latency per route every 1000 requests
+// and every minute. It is a separate middleware, use it next to GinLogger.
+func (l *Logger) GinLatencyTracker() gin.HandlerFunc {
+	return l.GinLatencyTrackerWithConfig(GinLatencyConfig{EveryN: 1000, Interval: time.Minute})
+}
+
+// GinLatencyTrackerWithConfig is GinLatencyTracker with the summary schedule of cfg.
+// Every summary starts a new window, so the percentiles cover the requests since the last one.
+// The percentiles are accurate to about 3%, the latencies are counted in histograms.
+func (l *Logger) GinLatencyTrackerWithConfig(cfg GinLatencyConfig) gin.HandlerFunc {
+	tracker := &latencyTracker{latencies: make(map[latencyRoute]*histogram)}
+
+	if cfg.Interval > 0 {
+		l.pending().background(func(stopped <-chan struct{}) {
+			ticker := time.NewTicker(cfg.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-stopped:
+					return
+				case <-ticker.C:
+					l.writeLatencySummary(tracker.reset())
+				}
+			}
+		})
+	}
+
+	return func(c *gin.Context) {
+		t := time.Now()
+		// process request
+		c.Next()
+		latency := time.Since(t)
+
+		key := latencyRoute{method: c.Request.Method, route: routePattern(c)}
+		if window := tracker.add(key, latency, cfg.EveryN); window != nil {
+			l.writeLatencySummary(window)
+		}
+	}
+}
+
+// add records a request and returns the window once everyN requests were recorded
+func (t *latencyTracker) add(key latencyRoute, latency time.Duration, everyN int) map[latencyRoute]*histogram {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.latencies[key]
+	if !ok {
+		if len(t.latencies) >= maxLatencyRoutes {
+			key = latencyRoute{method: key.method, route: otherLatencyRoute}
+			h = t.latencies[key]
+		}
+		if h == nil {
+			h = &histogram{}
+			t.latencies[key] = h
+		}
+	}
+	h.record(latency)
+	t.requests++
+
+	if everyN > 0 && t.requests >= everyN {
+		return t.resetLocked()
+	}
+	return nil
+}
+
+// reset returns the current window and starts a new one
+func (t *latencyTracker) reset() map[latencyRoute]*histogram {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.resetLocked()
+}
+
+// resetLocked is reset with t.mu held
+func (t *latencyTracker) resetLocked() map[latencyRoute]*histogram {
+	window := t.latencies
+	t.latencies = make(map[latencyRoute]*histogram)
+	t.requests = 0
+	return window
+}
+
+// writeLatencySummary writes a line with the percentiles of every route in the window
+func (l *Logger) writeLatencySummary(window map[latencyRoute]*histogram) {
+	keys := make([]latencyRoute, 0, len(window))
+	for key := range window {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].method < keys[j].method
+	})
+
+	for _, key := range keys {
+		h := window[key]
+		l.write(LevelInfo, 1, "[GIN] latency summary",
+			Field{Key: "method", Value: key.method},
+			Field{Key: "route", Value: key.route},
+			Field{Key: "count", Value: h.count},
+			Field{Key: "p50", Value: h.percentile(50)},
+			Field{Key: "p95", Value: h.percentile(95)},
+			Field{Key: "p99", Value: h.percentile(99)},
+		)
+	}
+}
+
+// routePattern rebuilds the route a request matched from its path and params,
+// e.g. /users/:id for /users/42, as gin does not keep the pattern on the context.
+func routePattern(c *gin.Context) string {
+	path := c.Request.URL.Path
+	if len(c.Params) == 0 {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	next := 0
+	for _, p := range c.Params {
+		// a catch-all param holds the rest of the path
+		if strings.HasPrefix(p.Value, "/") {
+			return strings.TrimSuffix(strings.Join(segments, "/"), p.Value) + "/*" + p.Key
+		}
+
+		for i := next; i < len(segments); i++ {
+			if segments[i] == p.Value {
+				segments[i] = ":" + p.Key
+				next = i + 1
+				break
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,63 @@
+package applogger
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGinLatencyTracker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelInfo, &buf)
+
+	r := gin.New()
+	r.Use(l.GinLatencyTrackerWithConfig(GinLatencyConfig{EveryN: 4}))
+	r.GET("/users/:id", func(c *gin.Context) {})
+	r.GET("/health", func(c *gin.Context) {})
+
+	for _, path := range []string{"/users/1", "/users/2", "/health", "/users/3"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "[GIN] latency summary method=GET route=/users/:id count=3 p50=") {
+		t.Errorf("the route summary is missing:\n%s", out)
+	}
+	if !strings.Contains(out, "route=/health count=1 ") {
+		t.Errorf("the health summary is missing:\n%s", out)
+	}
+
+	// The next window starts empty
+	buf.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if buf.Len() != 0 {
+		t.Errorf("summary written before EveryN requests:\n%s", buf.String())
+	}
+}
+
+func TestLatencyTrackerRoutesBounded(t *testing.T) {
+	tracker := &latencyTracker{latencies: make(map[latencyRoute]*histogram)}
+	for i := 0; i < maxLatencyRoutes+10; i++ {
+		tracker.add(latencyRoute{method: http.MethodGet, route: fmt.Sprintf("/r%d", i)}, time.Millisecond, 0)
+	}
+
+	window := tracker.reset()
+	if len(window) != maxLatencyRoutes+1 {
+		t.Errorf("%d routes tracked, want %d", len(window), maxLatencyRoutes+1)
+	}
+	if other := window[latencyRoute{method: http.MethodGet, route: otherLatencyRoute}]; other == nil || other.count != 10 {
+		t.Errorf("the requests past the bound were not counted as %s", otherLatencyRoute)
+	}
+	if len(tracker.latencies) != 0 || tracker.requests != 0 {
+		t.Error("reset did not start a new window")
+	}
+}
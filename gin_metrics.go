@@ -0,0 +1,70 @@
+package applogger
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinMetrics counts the requests handled by gin and their durations by route,
+// method and status, and serves them in the OpenMetrics text format for
+// Prometheus to scrape. It is kept next to the log, use it with GinLogger.
+type GinMetrics struct {
+	duration *metricVec
+	requests *metricVec
+
+	// routes bounds the routes counted, the rest are counted as otherLatencyRoute
+	mu     sync.Mutex
+	routes map[string]bool
+}
+
+// NewGinMetrics returns the metrics http_request_duration_seconds, a histogram,
+// and http_requests, a counter
+func NewGinMetrics() *GinMetrics {
+	return &GinMetrics{
+		duration: newHistogramVec("http_request_duration_seconds", "Duration of the HTTP requests.", "route", "method", "status"),
+		requests: newCounterVec("http_requests", "Number of HTTP requests.", "route", "method", "status"),
+		routes:   make(map[string]bool),
+	}
+}
+
+// GinMetricsMiddleware counts every request once it is handled. Routes are
+// labeled by pattern, e.g. /users/:id, the routes past the first 1000 as OTHER.
+func (m *GinMetrics) GinMetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t := time.Now()
+		// process request
+		c.Next()
+		latency := time.Since(t)
+
+		route := m.route(routePattern(c))
+		status := strconv.Itoa(c.Writer.Status())
+		m.duration.observe(latency, route, c.Request.Method, status)
+		m.requests.observe(latency, route, c.Request.Method, status)
+	}
+}
+
+// route returns the label of a route pattern
+func (m *GinMetrics) route(pattern string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.routes[pattern] {
+		if len(m.routes) >= maxLatencyRoutes {
+			return otherLatencyRoute
+		}
+		m.routes[pattern] = true
+	}
+	return pattern
+}
+
+// Handler serves the metrics in the OpenMetrics text format, e.g. on /metrics
+func (m *GinMetrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", openMetricsContentType)
+		writeOpenMetrics(w, m.duration, m.requests)
+	})
+}
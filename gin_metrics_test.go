@@ -0,0 +1,96 @@
+package applogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricVecHistogram(t *testing.T) {
+	m := newHistogramVec("job_duration_seconds", "Duration of the \"jobs\".", "job")
+	m.observe(3*time.Millisecond, "import")
+	m.observe(70*time.Millisecond, "import")
+	m.observe(time.Minute, "import")
+	m.observe(time.Millisecond, "a\"b")
+
+	var buf bytes.Buffer
+	if err := writeOpenMetrics(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# TYPE job_duration_seconds histogram
+# HELP job_duration_seconds Duration of the \"jobs\".
+job_duration_seconds_bucket{job="a\"b",le="0.005"} 1
+`
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("unexpected start:\n%s", buf.String())
+	}
+	for _, line := range []string{
+		`job_duration_seconds_bucket{job="import",le="0.005"} 1`,
+		`job_duration_seconds_bucket{job="import",le="0.05"} 1`,
+		`job_duration_seconds_bucket{job="import",le="0.1"} 2`,
+		`job_duration_seconds_bucket{job="import",le="10"} 2`,
+		`job_duration_seconds_bucket{job="import",le="+Inf"} 3`,
+		`job_duration_seconds_count{job="import"} 3`,
+		`job_duration_seconds_sum{job="import"} 60.073`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("%s missing in:\n%s", line, buf.String())
+		}
+	}
+	if !strings.HasSuffix(buf.String(), "\n# EOF\n") {
+		t.Error("the # EOF marker is missing")
+	}
+}
+
+func TestGinMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := NewGinMetrics()
+	r := gin.New()
+	r.Use(m.GinMetricsMiddleware())
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("Content-Type %q", ct)
+	}
+
+	out := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE http_request_duration_seconds histogram",
+		`http_request_duration_seconds_count{route="/users/:id",method="GET",status="200"} 2`,
+		"# TYPE http_requests counter",
+		`http_requests_total{route="/users/:id",method="GET",status="200"} 2`,
+		`http_requests_total{route="/missing",method="GET",status="404"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("%s missing in:\n%s", line, out)
+		}
+	}
+}
+
+func TestGinMetricsRoutesBounded(t *testing.T) {
+	m := NewGinMetrics()
+	for i := 0; i < maxLatencyRoutes; i++ {
+		m.route("/r" + strconv.Itoa(i))
+	}
+	if got := m.route("/new"); got != otherLatencyRoute {
+		t.Errorf("route past the bound = %s, want %s", got, otherLatencyRoute)
+	}
+	if got := m.route("/r1"); got != "/r1" {
+		t.Errorf("known route = %s", got)
+	}
+}
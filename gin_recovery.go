@@ -0,0 +1,50 @@
+package applogger
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinRecovery recovers a panic in a gin handler, writes it as an Error entry
+// and responds with a 500 and {"error": "internal server error"}.
+// The entry has the panic value, the stack trace, the X-Request-ID header,
+// the route and the client IP as fields.
+func (l *Logger) GinRecovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			var value string
+			switch v := r.(type) {
+			case error:
+				value = v.Error()
+			case string:
+				value = v
+			default:
+				value = fmt.Sprint(v)
+			}
+
+			fields := []Field{
+				{Key: "panic", Value: value},
+				{Key: "stack", Value: string(debug.Stack())},
+				{Key: "route", Value: routePattern(c)},
+				{Key: "client_ip", Value: c.ClientIP()},
+			}
+			if requestID := c.GetHeader("X-Request-ID"); requestID != "" {
+				fields = append(fields, Field{Key: "request_id", Value: requestID})
+			}
+
+			l.write(LevelError, 1, fmt.Sprintf("[GIN] %s %s : panic recovered", c.Request.Method, c.Request.URL.Path), fields...)
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}()
+
+		c.Next()
+	}
+}
@@ -2,4 +2,7 @@ module github.com/codingmechanics/applogger
 
 go 1.12
 
-require github.com/gin-gonic/gin v1.4.0
+require (
+	github.com/gin-gonic/gin v1.4.0
+	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037
+)
@@ -0,0 +1,66 @@
+package applogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+)
+
+// headerKey marks the header line of JSON log files, readers of the entries skip it
+const headerKey = "log_header"
+
+// writeFileHeader writes the block describing the process at the top of a new
+// log file. Text files get "# key: value" comment lines, JSON files get a
+// single JSON object line with "log_header":true, so the file stays JSONL.
+func (l *Logger) writeFileHeader(w io.Writer, logLevel int32) {
+	now := time.Now()
+	if l.DataTimeUTC {
+		now = now.UTC()
+	}
+
+	hostname, _ := os.Hostname()
+
+	format := l.Format
+	if format == "" {
+		format = FormatText
+	}
+
+	header := []Field{
+		{Key: "start_time", Value: now.Format(time.RFC3339)},
+		{Key: "hostname", Value: hostname},
+		{Key: "pid", Value: os.Getpid()},
+		{Key: "go_version", Value: runtime.Version()},
+		{Key: "log_level", Value: LevelString(logLevel)},
+		{Key: "format", Value: format},
+	}
+
+	// The build info of SetBuildInfo is written with every line instead
+	if l.AppVersion != "" {
+		header = append(header, Field{Key: "app_version", Value: l.AppVersion})
+	}
+	if l.BuildCommit != "" {
+		header = append(header, Field{Key: "build_commit", Value: l.BuildCommit})
+	}
+
+	if format.isJSON() {
+		values := make(map[string]interface{}, len(header)+1)
+		values[headerKey] = true
+		for _, f := range header {
+			values[f.Key] = f.Value
+		}
+
+		b, err := json.Marshal(values)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "%s\n", b)
+		return
+	}
+
+	for _, f := range header {
+		fmt.Fprintf(w, "# %s: %v\n", f.Key, f.Value)
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFileHeaderJSONL(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{Format: FormatJSON, WriteFileHeader: true, AppVersion: "1.2.3"}
+	a, err := l.TryStartFile(LevelInfo, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("first entry")
+	path := a.LogFile.Name()
+	l.Stop()
+
+	scanner := bufio.NewScanner(strings.NewReader(readFile(t, path)))
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %d is not JSON: %q", len(lines)+1, scanner.Text())
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) < 2 {
+		t.Fatalf("got %d lines, want the header and the entry", len(lines))
+	}
+	header := lines[0]
+	if header[headerKey] != true || header["log_level"] != "info" || header["app_version"] != "1.2.3" || header["format"] != "json" {
+		t.Errorf("unexpected header %v", header)
+	}
+	if lines[1]["message"] != "first entry" {
+		t.Errorf("unexpected entry %v", lines[1])
+	}
+}
+
+func TestFileHeaderText(t *testing.T) {
+	var buf syncBuffer
+	l := &Logger{WriteFileHeader: true}
+	l.writeFileHeader(&buf, LevelWarn)
+
+	out := buf.String()
+	for _, want := range []string{"# start_time: ", "# pid: ", "# log_level: warn\n", "# format: text\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("header misses %q:\n%s", want, out)
+		}
+	}
+}
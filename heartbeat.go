@@ -0,0 +1,27 @@
+package applogger
+
+import "time"
+
+// Heartbeat writes msg at Info level every interval until Stop is called.
+// When the heartbeat stops showing up in the log the process is hung.
+// It returns ErrInvalidInterval for an interval of 0 or less.
+func (l *Logger) Heartbeat(interval time.Duration, msg string) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	l.pending().background(func(stopped <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopped:
+				return
+			case <-ticker.C:
+				l.Info("%s", msg)
+			}
+		}
+	})
+	return nil
+}
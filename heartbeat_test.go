@@ -0,0 +1,43 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	l := &Logger{}
+	l.StartWriter(LevelInfo, &syncBuffer{})
+	hook := NewMemoryHook(10, LevelInfo)
+	l.AddHook(hook)
+
+	if err := l.Heartbeat(0, "alive"); err != ErrInvalidInterval {
+		t.Errorf("Heartbeat(0) = %v, want ErrInvalidInterval", err)
+	}
+	if err := l.Heartbeat(5*time.Millisecond, "alive"); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	l.Stop()
+	beats := 0
+	for _, e := range hook.Entries() {
+		if strings.HasSuffix(e.Message, "alive") {
+			beats++
+		}
+	}
+	if beats == 0 {
+		t.Fatal("no heartbeat was written")
+	}
+
+	// Stop ends the heartbeat
+	after := len(hook.Entries())
+	time.Sleep(20 * time.Millisecond)
+	if len(hook.Entries()) != after {
+		t.Error("the heartbeat kept writing after Stop")
+	}
+}
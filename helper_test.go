@@ -0,0 +1,73 @@
+package applogger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// tempDir creates a directory removed once the test ends
+func tempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "applogger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// readFile returns the content of the file at path
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// readLogFile returns the content of the log file StartFile created in dir
+func readLogFile(t *testing.T, dir string) string {
+	t.Helper()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "*.txt"))
+	if len(files) != 1 {
+		t.Fatalf("log files = %v", files)
+	}
+	return readFile(t, files[0])
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the background goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// callerLine returns file:line of the line after the call of callerLine
+func callerLine(t *testing.T) string {
+	_, file, line, ok := runtime.Caller(1)
+	if !ok {
+		t.Fatal("no caller")
+	}
+	return filepath.Base(file) + ":" + strconv.Itoa(line+1)
+}
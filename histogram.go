@@ -0,0 +1,87 @@
+package applogger
+
+import (
+	"math/bits"
+	"time"
+)
+
+// histogramSubBits is the precision of a histogram bucket: durations of up to
+// 2^histogramSubBits microseconds are counted exactly, longer ones in buckets
+// at most 1/2^(histogramSubBits-1), about 3%, wide.
+const histogramSubBits = 6
+
+// histogramMaxExp bounds the durations counted, longer ones are counted as
+// about 2^(histogramMaxExp+histogramSubBits) microseconds, 19 hours
+const histogramMaxExp = 31
+
+const (
+	histogramSubBuckets = 1 << histogramSubBits
+	histogramHalf       = histogramSubBuckets / 2
+	histogramBuckets    = histogramSubBuckets + histogramMaxExp*histogramHalf
+)
+
+// histogram counts durations in buckets of bounded relative error, like an
+// HdrHistogram, so its size does not grow with the number of durations recorded
+type histogram struct {
+	counts [histogramBuckets]uint32
+	count  uint64
+}
+
+// record adds d to the histogram
+func (h *histogram) record(d time.Duration) {
+	h.counts[histogramIndex(d)]++
+	h.count++
+}
+
+// percentile returns the nearest rank percentile p of the recorded durations,
+// the highest duration of its bucket
+func (h *histogram) percentile(p int) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := (uint64(p)*h.count + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	var seen uint64
+	for i, n := range h.counts {
+		seen += uint64(n)
+		if seen >= rank {
+			return histogramUpper(i)
+		}
+	}
+	return histogramUpper(histogramBuckets - 1)
+}
+
+// histogramIndex returns the bucket of d
+func histogramIndex(d time.Duration) int {
+	us := uint64(0)
+	if d > 0 {
+		us = uint64(d / time.Microsecond)
+	}
+	if us < histogramSubBuckets {
+		return int(us)
+	}
+
+	// keep the histogramSubBits highest bits of us
+	exp := bits.Len64(us) - histogramSubBits
+	if exp > histogramMaxExp {
+		return histogramBuckets - 1
+	}
+	sub := int(us>>uint(exp)) - histogramHalf
+	return histogramSubBuckets + (exp-1)*histogramHalf + sub
+}
+
+// histogramUpper returns the highest duration counted in bucket i
+func histogramUpper(i int) time.Duration {
+	if i < histogramSubBuckets {
+		return time.Duration(i) * time.Microsecond
+	}
+
+	i -= histogramSubBuckets
+	exp := uint(i/histogramHalf + 1)
+	sub := uint64(i%histogramHalf + histogramHalf)
+	return time.Duration((sub+1)<<exp-1) * time.Microsecond
+}
@@ -0,0 +1,59 @@
+package applogger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramIndex(t *testing.T) {
+	previous := -1
+	for us := 0; us < 1<<20; us += 7 {
+		d := time.Duration(us) * time.Microsecond
+		i := histogramIndex(d)
+		if i < previous {
+			t.Fatalf("index of %s is %d, below %d", d, i, previous)
+		}
+		previous = i
+
+		upper := histogramUpper(i)
+		if upper < d {
+			t.Fatalf("bucket %d of %s ends at %s", i, d, upper)
+		}
+		if us >= histogramSubBuckets && float64(upper-d) > float64(d)/float64(histogramHalf) {
+			t.Fatalf("bucket %d of %s ends at %s, more than %d%% off", i, d, upper, 100/histogramHalf)
+		}
+	}
+
+	if i := histogramIndex(1000 * time.Hour); i != histogramBuckets-1 {
+		t.Errorf("index of 1000h = %d, want the last bucket", i)
+	}
+	if i := histogramIndex(-time.Second); i != 0 {
+		t.Errorf("index of a negative duration = %d", i)
+	}
+}
+
+func TestHistogramPercentile(t *testing.T) {
+	var h histogram
+	if p := h.percentile(99); p != 0 {
+		t.Errorf("percentile of an empty histogram = %s", p)
+	}
+
+	for i := 1; i <= 100; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+
+	for _, tt := range []struct {
+		p    int
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{95, 95 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	} {
+		got := h.percentile(tt.p)
+		if got < tt.want || got > tt.want+tt.want/histogramHalf {
+			t.Errorf("p%d = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+}
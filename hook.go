@@ -0,0 +1,170 @@
+package applogger
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Hook is called with every line written at one of its Levels, e.g. to send
+// errors to an alerting system. Fire runs while the line is being logged.
+type Hook interface {
+	Levels() []int32
+	Fire(level int32, message string) error
+}
+
+// AddHook calls h with the lines written from now on at the levels of h.
+// A failing hook is reported to stderr and does not fail the log call.
+func (l *Logger) AddHook(h Hook) {
+	a := l.pending()
+
+	a.hookMu.Lock()
+	a.hooks = append(append([]Hook(nil), a.hooks...), h)
+	a.hookMu.Unlock()
+}
+
+// hookLevelWriter writes to the level writer and then fires the hooks of the level
+type hookLevelWriter struct {
+	w     io.Writer
+	level int32
+	a     *ApplicationLog
+}
+
+// hookWriter wraps a level writer so the hooks are fired for its lines,
+// levels that are not logged keep writing to ioutil.Discard
+func (a *ApplicationLog) hookWriter(level int32, w io.Writer) io.Writer {
+	if w == ioutil.Discard {
+		return w
+	}
+	return &hookLevelWriter{w: w, level: level, a: a}
+}
+
+// Write writes p to the level writer and fires the hooks
+func (hw *hookLevelWriter) Write(p []byte) (int, error) {
+	n, err := hw.w.Write(p)
+
+	hw.a.hookMu.Lock()
+	hooks := hw.a.hooks
+	hw.a.hookMu.Unlock()
+
+	if len(hooks) == 0 {
+		return n, err
+	}
+
+	message := strings.TrimSuffix(string(p), "\n")
+	for _, h := range hooks {
+		if !hasLevel(h.Levels(), hw.level) {
+			continue
+		}
+		if herr := h.Fire(hw.level, message); herr != nil {
+			fmt.Fprintf(os.Stderr, "applogger: hook failed: %s\n", herr)
+		}
+	}
+	return n, err
+}
+
+// hasLevel reports whether levels contains level
+func hasLevel(levels []int32, level int32) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryEntry is a line kept by MemoryHook
+type MemoryEntry struct {
+	Level   int32
+	Message string
+}
+
+// memoryRing keeps the last entries added, replacing the oldest once it is
+// full. MemoryHook and MemoryHandler keep their lines in it.
+type memoryRing struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	full    bool
+}
+
+// newMemoryRing creates a memoryRing keeping the last size entries
+func newMemoryRing(size int) *memoryRing {
+	if size < 1 {
+		size = 1
+	}
+	return &memoryRing{entries: make([]LogEntry, size)}
+}
+
+// add keeps e, replacing the oldest entry once the ring is full
+func (r *memoryRing) add(e LogEntry) {
+	r.mu.Lock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// all returns the kept entries, oldest first
+func (r *memoryRing) all() []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]LogEntry(nil), r.entries[:r.next]...)
+	}
+	return append(append([]LogEntry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// reset drops the kept entries
+func (r *memoryRing) reset() {
+	r.mu.Lock()
+	r.entries = make([]LogEntry, len(r.entries))
+	r.next = 0
+	r.full = false
+	r.mu.Unlock()
+}
+
+// MemoryHook keeps the last lines written in a ring buffer, e.g. to check
+// the log output in tests
+type MemoryHook struct {
+	levels []int32
+	ring   *memoryRing
+}
+
+// NewMemoryHook creates a MemoryHook keeping the last size lines of levels,
+// of every level when none are given
+func NewMemoryHook(size int, levels ...int32) *MemoryHook {
+	if len(levels) == 0 {
+		levels = []int32{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError}
+	}
+	return &MemoryHook{
+		levels: levels,
+		ring:   newMemoryRing(size),
+	}
+}
+
+// Levels returns the levels the hook keeps
+func (h *MemoryHook) Levels() []int32 {
+	return h.levels
+}
+
+// Fire keeps the line, replacing the oldest once the buffer is full
+func (h *MemoryHook) Fire(level int32, message string) error {
+	h.ring.add(LogEntry{Level: level, Message: message})
+	return nil
+}
+
+// Entries returns the kept lines, oldest first
+func (h *MemoryHook) Entries() []MemoryEntry {
+	var entries []MemoryEntry
+	for _, e := range h.ring.all() {
+		entries = append(entries, MemoryEntry{Level: e.Level, Message: e.Message})
+	}
+	return entries
+}
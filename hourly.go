@@ -0,0 +1,181 @@
+package applogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// hourlyFile writes to the log file of the current hour, StartFileHourly
+// switches it to a new file at the top of every hour
+type hourlyFile struct {
+	l        *Logger
+	logLevel int32
+	base     string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// StartFileHourly is StartFile for applications logging too much for a file
+// a day: the log continues in a new file at the top of every hour, e.g.
+// 2006-01-02/15/2006-01-02T15-00-00.txt, and the hour directories older
+// than hoursToKeep are removed. Stop ends the hourly rotation. It returns
+// the error when the directory or the file cannot be created.
+func (l *Logger) StartFileHourly(logLevel int32, baseFilePath string, hoursToKeep int) (*ApplicationLog, error) {
+	logf, baseFilePath, err := l.createLogFile(baseFilePath, hourlyDirLayout)
+	if err != nil {
+		return nil, err
+	}
+
+	a := l.start()
+	h := &hourlyFile{l: l, logLevel: envLevel(logLevel), base: baseFilePath, file: logf}
+	l.startOnFile(a, logLevel, logf, h, nil)
+
+	a.background(func(stopped <-chan struct{}) {
+		for {
+			now := l.now()
+			timer := time.NewTimer(nextHour(now).Sub(now))
+
+			select {
+			case <-stopped:
+				timer.Stop()
+				return
+			case <-timer.C:
+				h.reopen()
+				l.LogHourlyCleanup(baseFilePath, hoursToKeep)
+			}
+		}
+	})
+
+	// Cleanup any existing directories
+	l.LogHourlyCleanup(baseFilePath, hoursToKeep)
+	return a, nil
+}
+
+// hourOf returns the start of the hour of t plus hours, in the location of t.
+// Unlike Truncate it follows the clock of the location, e.g. in time zones
+// with a half hour offset.
+func hourOf(t time.Time, hours int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+hours, 0, 0, 0, t.Location())
+}
+
+// nextHour returns the top of the hour following t
+func nextHour(t time.Time) time.Time {
+	return hourOf(t, 1)
+}
+
+// Write writes p to the file of the current hour
+func (h *hourlyFile) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.file.Write(p)
+}
+
+// reopen switches to the file of the current hour
+func (h *hourlyFile) reopen() {
+	a := h.l.instance()
+
+	next, _, err := h.l.createLogFile(h.base, hourlyDirLayout)
+	if err != nil {
+		a.writeFailed(LevelError, err)
+		return
+	}
+	h.l.continueFile(next, h.logLevel)
+
+	h.mu.Lock()
+	previous := h.file
+	h.file = next
+	a.mu.Lock()
+	a.LogFile = next
+	a.mu.Unlock()
+	h.mu.Unlock()
+
+	previous.Close()
+}
+
+// switchFile implements fileSwitcher
+func (h *hourlyFile) switchFile(current, next *os.File) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file != current {
+		return false
+	}
+	h.file = next
+
+	a := h.l.instance()
+	a.mu.Lock()
+	a.LogFile = next
+	a.mu.Unlock()
+	return true
+}
+
+// LogHourlyCleanup removes the hour directories of StartFileHourly older than
+// hoursToKeep, and the date directories left empty
+func (l *Logger) LogHourlyCleanup(baseFilePath string, hoursToKeep int) {
+	l.Startedf("LogHourlyCleanup", "BaseFilePath[%s] HoursToKeep[%d]", baseFilePath, hoursToKeep)
+
+	dayInfos, err := ioutil.ReadDir(baseFilePath)
+	if err != nil {
+		l.CompletedError("LogHourlyCleanup", err)
+		return
+	}
+
+	now := l.now()
+	compareHour := hourOf(now, -hoursToKeep)
+	l.Debug("LogHourlyCleanup : CompareHour[%v]", compareHour)
+
+	for _, dayInfo := range dayInfos {
+		if !dayInfo.IsDir() {
+			continue
+		}
+
+		// The directory name look like: YYYY-MM-DD
+		day, err := time.ParseInLocation("2006-01-02", dayInfo.Name(), now.Location())
+		if err != nil {
+			l.ErrorWith(err, "LogHourlyCleanup : Attempting To Convert Directory [%s] :", dayInfo.Name())
+			continue
+		}
+
+		dayPath := fmt.Sprintf("%s/%s", baseFilePath, dayInfo.Name())
+		hourInfos, err := ioutil.ReadDir(dayPath)
+		if err != nil {
+			l.ErrorWith(err, "LogHourlyCleanup : Attempting To Read Directory [%s] :", dayPath)
+			continue
+		}
+
+		kept := len(hourInfos)
+		for _, hourInfo := range hourInfos {
+			hour, err := strconv.Atoi(hourInfo.Name())
+			if !hourInfo.IsDir() || err != nil {
+				continue
+			}
+
+			hourPath := fmt.Sprintf("%s/%s", dayPath, hourInfo.Name())
+			if !time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, day.Location()).Before(compareHour) {
+				continue
+			}
+
+			l.Debug("LogHourlyCleanup : Removing Directory[%s]", hourPath)
+			if err := l.notifyCleanup(hourPath, cleanupReasonAge); err != nil {
+				l.ErrorWith(err, "LogHourlyCleanup : OnCleanup Skipped Directory [%s] :", hourPath)
+				continue
+			}
+			if err := os.RemoveAll(hourPath); err != nil {
+				l.ErrorWith(err, "LogHourlyCleanup : Failed to Remove Directory [%s] :", hourPath)
+				continue
+			}
+			kept--
+		}
+
+		if kept == 0 {
+			os.Remove(dayPath)
+		}
+	}
+
+	l.Completed("LogHourlyCleanup")
+}
@@ -0,0 +1,110 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextHour(t *testing.T) {
+	india := time.FixedZone("IST", 5*60*60+30*60)
+	for _, tt := range []struct {
+		now, want time.Time
+	}{
+		{time.Date(2024, 1, 15, 10, 45, 0, 0, india), time.Date(2024, 1, 15, 11, 0, 0, 0, india)},
+		{time.Date(2024, 1, 15, 10, 0, 0, 0, india), time.Date(2024, 1, 15, 11, 0, 0, 0, india)},
+		{time.Date(2024, 1, 15, 23, 59, 59, 0, india), time.Date(2024, 1, 16, 0, 0, 0, 0, india)},
+		{time.Date(2024, 12, 31, 23, 30, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		if got := nextHour(tt.now); !got.Equal(tt.want) {
+			t.Errorf("nextHour(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestStartFileHourly(t *testing.T) {
+	base := tempDir(t)
+
+	var files int
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.FileNameFunc = func(t time.Time) string {
+		files++
+		return fmt.Sprintf("%s-%d.txt", t.Format("2006-01-02T15"), files)
+	}
+	a, err := l.StartFileHourly(LevelError, base, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	first := a.LogFile
+	dir := filepath.Join(base, time.Now().Format(hourlyDirLayout))
+	if filepath.Dir(first.Name()) != dir {
+		t.Errorf("file %s, want it in %s", first.Name(), dir)
+	}
+
+	l.Info("first hour")
+	// what the timer does at the top of the hour
+	a.logOut.(*hourlyFile).reopen()
+	l.Info("next hour")
+
+	second := a.LogFile
+	if second == first {
+		t.Fatal("reopen kept the file")
+	}
+	if out := readFile(t, first.Name()); !strings.Contains(out, "first hour\n") || strings.Contains(out, "next hour") {
+		t.Errorf("first file:\n%s", out)
+	}
+	if out := readFile(t, second.Name()); !strings.Contains(out, "next hour\n") {
+		t.Errorf("second file:\n%s", out)
+	}
+	if _, err := first.Write([]byte("x")); err == nil {
+		t.Error("the file of the previous hour is still open")
+	}
+}
+
+func TestStartFileHourlyError(t *testing.T) {
+	file := filepath.Join(tempDir(t), "file")
+	if err := ioutil.WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Logger{}).StartFileHourly(LevelInfo, file, 24); err == nil {
+		t.Error("StartFileHourly under a file returned no error")
+	}
+}
+
+func TestLogHourlyCleanup(t *testing.T) {
+	base := tempDir(t)
+	now := time.Now()
+
+	var dirs []string
+	for _, hours := range []int{0, 1, 2, 3, 30} {
+		dir := filepath.Join(base, hourOf(now, -hours).Format(hourlyDirLayout))
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	l := &Logger{FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, ioutil.Discard)
+	l.LogHourlyCleanup(base, 2)
+
+	for i, dir := range dirs {
+		_, err := os.Stat(dir)
+		if kept := err == nil; kept != (i <= 2) {
+			t.Errorf("%s kept %v", dir, kept)
+		}
+	}
+	// 30 hours ago is on another day than the hours kept, its day is left empty
+	if _, err := os.Stat(filepath.Dir(dirs[4])); err == nil {
+		t.Errorf("the empty day directory %s was kept", filepath.Dir(dirs[4]))
+	}
+}
@@ -0,0 +1,75 @@
+package applogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jsonReserved are the keys FormatJSON writes itself, fields with these keys are skipped
+var jsonReserved = map[string]bool{
+	"level":     true,
+	"timestamp": true,
+	"caller":    true,
+	"function":  true,
+	"message":   true,
+}
+
+// jsonEvent formats the entry as a single JSON object, the fields of the
+// entry follow the fixed keys at the top level. The fingerprint of the entry
+// is written after the fixed keys when fingerprint is set.
+func jsonEvent(e *LogEntry, fingerprint bool) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+
+	writeJSON := func(key string, value interface{}) error {
+		v, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+		return nil
+	}
+
+	fixed := []Field{
+		{Key: "level", Value: strings.ToLower(textLevel(e))},
+		{Key: "timestamp", Value: e.Timestamp.Format(time.RFC3339Nano)},
+		{Key: "caller", Value: e.Caller},
+		{Key: "function", Value: e.Function},
+		{Key: "message", Value: e.Message},
+	}
+	if fingerprint {
+		fixed = append(fixed, Field{Key: "fingerprint", Value: e.Fingerprint()})
+	}
+	for _, f := range fixed {
+		if err := writeJSON(f.Key, f.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range e.Fields {
+		if jsonReserved[f.Key] || (fingerprint && f.Key == "fingerprint") {
+			continue
+		}
+
+		// errors marshal to {}, values json can not hold are written as text
+		value := f.Value
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		if err := writeJSON(f.Key, value); err != nil {
+			writeJSON(f.Key, fmt.Sprint(value))
+		}
+	}
+
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
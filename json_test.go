@@ -0,0 +1,88 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatJSON}
+	l.StartWriter(LevelTrace, &buf)
+
+	l.Started("Load")
+	l.Startedf("Load", "Path[%s]", "a.txt")
+	l.Completed("Load")
+	l.Completedf("Load", "Lines[%d]", 3)
+	l.CompletedError("Load", errors.New("disk full"))
+	l.Trace("trace %d", 1)
+	l.Debug("debug \"quoted\"")
+	l.DebugCtx(WithRequestID(context.Background(), "abc123"), "debug ctx")
+	l.Info("info\nwith a newline")
+	l.InfoFields("info fields", Field{Key: "n", Value: 1})
+	l.Warning("warning %s", "text")
+	l.Error("error")
+	l.ErrorWith(errors.New("timeout"), "Load : Failed [%s]", "a.txt")
+	l.ErrorG("error %d", 2)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 14 {
+		t.Fatalf("%d lines, want 14:\n%s", len(lines), buf.String())
+	}
+
+	for _, line := range lines {
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Errorf("invalid JSON line %q: %s", line, err)
+			continue
+		}
+		for _, key := range []string{"level", "timestamp", "caller", "function", "message"} {
+			if _, ok := event[key]; !ok {
+				t.Errorf("%s missing in %s", key, line)
+			}
+		}
+		if !strings.HasPrefix(event["caller"].(string), "json_test.go:") {
+			t.Errorf("caller of %s", line)
+		}
+		if strings.Contains(line, "\x1b[") {
+			t.Errorf("colored JSON line %q", line)
+		}
+	}
+
+	var info map[string]interface{}
+	json.Unmarshal([]byte(lines[9]), &info)
+	if info["level"] != "info" || info["message"] != "info fields" || info["n"] != float64(1) {
+		t.Errorf("unexpected fields line %v", info)
+	}
+}
+
+func TestJSONEventFields(t *testing.T) {
+	e := &LogEntry{
+		Level:   LevelError,
+		Message: "Load : Failed",
+		Fields: []Field{
+			{Key: "message", Value: "shadowed"},
+			{Key: "err", Value: errors.New("timeout")},
+			{Key: "ch", Value: make(chan int)},
+		},
+	}
+
+	line, err := jsonEvent(e, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal(line, &event); err != nil {
+		t.Fatalf("invalid JSON %s: %s", line, err)
+	}
+	if event["message"] != "Load : Failed" || event["err"] != "timeout" || event["ch"] == nil {
+		t.Errorf("unexpected event %s", line)
+	}
+}
@@ -0,0 +1,141 @@
+package applogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// levelNames are the names of the level bits, in the order LevelString writes them
+var levelNames = []struct {
+	level int32
+	name  string
+}{
+	{LevelTrace, "trace"},
+	{LevelDebug, "debug"},
+	{LevelInfo, "info"},
+	{LevelWarn, "warn"},
+	{LevelError, "error"},
+	{LevelFatal, "fatal"},
+}
+
+// levelOn reports whether starting at logLevel writes level. A single level
+// turns on every coarser level, several levels ORed together, e.g. by
+// ParseLevelMask("debug|warn"), turn on just those levels. Fatal lines go to
+// the Error destination, so Fatal counts as Error on both sides.
+func levelOn(level, logLevel int32) bool {
+	if level == LevelFatal {
+		level = LevelError
+	}
+	if logLevel&LevelFatal != 0 {
+		logLevel = logLevel&^LevelFatal | LevelError
+	}
+	if logLevel&(logLevel-1) != 0 {
+		return logLevel&level != 0
+	}
+
+	for _, n := range levelNames {
+		if logLevel&n.level != 0 {
+			return true
+		}
+		if n.level == level {
+			return false
+		}
+	}
+	return false
+}
+
+// IsLevelEnabled reports whether the lines of level are written, e.g. to skip
+// building an expensive message
+func (l *Logger) IsLevelEnabled(level int32) bool {
+	logger := l.instance().levelLogger(l.mapLevel(level))
+	return logger != nil && logger.Writer() != ioutil.Discard
+}
+
+// levelEnv names the environment variable holding the level used when the
+// logger is started with 0
+const levelEnv = "APPLOGGER_LEVEL"
+
+// envLevel returns logLevel, or the level of APPLOGGER_LEVEL when logLevel is 0
+func envLevel(logLevel int32) int32 {
+	if logLevel != 0 {
+		return logLevel
+	}
+
+	name := os.Getenv(levelEnv)
+	if name == "" {
+		return logLevel
+	}
+
+	level, err := ParseLevel(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "applogger: %s: unknown level %q\n", levelEnv, name)
+		return logLevel
+	}
+	return level
+}
+
+// ParseLevel returns the level constant named by s, e.g. debug, info, warn or
+// error, ErrUnknownLevel when s is not a level name
+func ParseLevel(s string) (int32, error) {
+	level, err := parseLevelName(s)
+	if err != nil {
+		return 0, ErrUnknownLevel
+	}
+	return level, nil
+}
+
+// ParseLevelMask parses levels joined by "|", e.g. "debug|warn", into the
+// level bits ORed together. The names are case insensitive, warning,
+// verbose and critical are accepted as well. A single level logs every
+// coarser level like the Level constants, several levels log just those
+// levels: "debug|warn" writes Debug and Warning lines but no Info or Error,
+// "debug|warn|error" keeps the errors.
+func ParseLevelMask(s string) (int32, error) {
+	var mask int32
+	for _, name := range strings.Split(s, "|") {
+		level, err := parseLevelName(name)
+		if err != nil {
+			return 0, err
+		}
+		mask |= level
+	}
+	return mask, nil
+}
+
+// parseLevelName returns the level of a single level name
+func parseLevelName(name string) (int32, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug", "verbose":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error", "critical":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	}
+	return 0, fmt.Errorf("applogger: unknown level %q", name)
+}
+
+// LevelString names the bits of level joined by "|", e.g. "debug|warn".
+// Bits without a name are written as a number.
+func LevelString(level int32) string {
+	var names []string
+	for _, n := range levelNames {
+		if level&n.level != 0 {
+			names = append(names, n.name)
+			level &^= n.level
+		}
+	}
+	if level != 0 || len(names) == 0 {
+		names = append(names, strconv.Itoa(int(level)))
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,110 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]int32{
+		"debug": LevelDebug,
+		"info":  LevelInfo,
+		"warn":  LevelWarn,
+		"error": LevelError,
+		"WARN ": LevelWarn,
+	} {
+		level, err := ParseLevel(name)
+		if err != nil || level != want {
+			t.Errorf("ParseLevel(%q) = %d, %v, want %d", name, level, err, want)
+		}
+	}
+
+	for _, name := range []string{"", "loud", "debug|warn"} {
+		if level, err := ParseLevel(name); err != ErrUnknownLevel || level != 0 {
+			t.Errorf("ParseLevel(%q) = %d, %v, want ErrUnknownLevel", name, level, err)
+		}
+	}
+}
+
+func TestParseLevelMask(t *testing.T) {
+	mask, err := ParseLevelMask("debug|warn")
+	if err != nil || mask != LevelDebug|LevelWarn {
+		t.Fatalf("ParseLevelMask(\"debug|warn\") = %d, %v", mask, err)
+	}
+
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(mask, &buf)
+
+	l.Debug("kept debug")
+	l.Info("dropped info")
+	l.Warning("kept warning")
+	l.Error("dropped error")
+
+	out := buf.String()
+	if !strings.Contains(out, "kept debug") || !strings.Contains(out, "kept warning") {
+		t.Errorf("the levels of the mask were not written:\n%s", out)
+	}
+	if strings.Contains(out, "dropped") {
+		t.Errorf("levels outside the mask were written:\n%s", out)
+	}
+
+	for level, want := range map[int32]map[int32]bool{
+		LevelWarn:               {LevelDebug: false, LevelInfo: false, LevelWarn: true, LevelError: true, LevelFatal: true},
+		LevelFatal:              {LevelWarn: false, LevelError: true, LevelFatal: true},
+		LevelDebug | LevelWarn:  {LevelTrace: false, LevelDebug: true, LevelInfo: false, LevelWarn: true, LevelError: false},
+		LevelInfo | LevelFatal:  {LevelInfo: true, LevelWarn: false, LevelError: true, LevelFatal: true},
+		LevelError | LevelFatal: {LevelWarn: false, LevelError: true},
+	} {
+		for line, on := range want {
+			if levelOn(line, level) != on {
+				t.Errorf("levelOn(%s, %s) = %v", LevelString(line), LevelString(level), !on)
+			}
+		}
+	}
+}
+
+func TestStartLevelEnv(t *testing.T) {
+	for _, tt := range []struct {
+		env      string
+		logLevel int32
+		debug    bool
+		warn     bool
+	}{
+		{"debug", 0, true, true},
+		{"warn", 0, false, true},
+		{"debug", LevelError, false, false},
+		{"loud", 0, false, false},
+		{"", 0, false, false},
+	} {
+		t.Setenv(levelEnv, tt.env)
+
+		l := &Logger{DisableColor: true}
+		l.Start(tt.logLevel)
+		if got := l.IsLevelEnabled(LevelDebug); got != tt.debug {
+			t.Errorf("%s=%s Start(%d): debug enabled %v, want %v", levelEnv, tt.env, tt.logLevel, got, tt.debug)
+		}
+		if got := l.IsLevelEnabled(LevelWarn); got != tt.warn {
+			t.Errorf("%s=%s Start(%d): warn enabled %v, want %v", levelEnv, tt.env, tt.logLevel, got, tt.warn)
+		}
+		l.Stop()
+	}
+}
+
+func TestStartFileLevelEnv(t *testing.T) {
+	t.Setenv(levelEnv, "info")
+
+	l := &Logger{DisableColor: true}
+	if _, err := l.TryStartFile(0, tempDir(t), 1); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	if !l.IsLevelEnabled(LevelInfo) || l.IsLevelEnabled(LevelDebug) {
+		t.Errorf("%s=info: info enabled %v, debug enabled %v", levelEnv, l.IsLevelEnabled(LevelInfo), l.IsLevelEnabled(LevelDebug))
+	}
+}
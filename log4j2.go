@@ -0,0 +1,104 @@
+package applogger
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// loggerName is reported as the logger and the default thread of log4j2 events
+	loggerName = "applogger"
+
+	// loggerFqcn is reported as the fully qualified class of log4j2 events
+	loggerFqcn = "github.com/codingmechanics/applogger.Logger"
+)
+
+// log4j2Instant is the instant object of the log4j2 JsonLayout
+type log4j2Instant struct {
+	EpochSecond  int64 `json:"epochSecond"`
+	NanoOfSecond int   `json:"nanoOfSecond"`
+}
+
+// log4j2JSON is a single event in the log4j2 JsonLayout.
+// Entry fields are written to the contextMap the same way log4j2 writes its ThreadContext.
+type log4j2JSON struct {
+	Instant    log4j2Instant `json:"instant"`
+	Thread     string        `json:"thread"`
+	Level      string        `json:"level"`
+	LoggerName string        `json:"loggerName"`
+	Message    string        `json:"message"`
+	EndOfBatch bool          `json:"endOfBatch"`
+	LoggerFqcn string        `json:"loggerFqcn"`
+	// Fingerprint is not part of the JsonLayout, it is only written with LogFingerprint
+	Fingerprint string                 `json:"fingerprint,omitempty"`
+	ContextMap  map[string]interface{} `json:"contextMap,omitempty"`
+}
+
+// log4j2Level maps the level of the entry to the log4j2 level name
+func log4j2Level(e *LogEntry) string {
+	if e.LevelName != "" {
+		return e.LevelName
+	}
+
+	switch e.Level {
+	case LevelTrace:
+		return "TRACE"
+	case LevelFatal:
+		return "FATAL"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// log4j2Event marshals the entry as a log4j2 JsonLayout event, with the
+// fingerprint of the entry when fingerprint is set
+func log4j2Event(e *LogEntry, logGoroutineID, fingerprint bool) ([]byte, error) {
+	event := log4j2JSON{
+		Instant: log4j2Instant{
+			EpochSecond:  e.Timestamp.Unix(),
+			NanoOfSecond: e.Timestamp.Nanosecond(),
+		},
+		Thread:     loggerName,
+		Level:      log4j2Level(e),
+		LoggerName: loggerName,
+		Message:    e.Message,
+		LoggerFqcn: loggerFqcn,
+	}
+	if fingerprint {
+		event.Fingerprint = e.Fingerprint()
+	}
+
+	if logGoroutineID {
+		event.Thread = "goroutine" + strconv.FormatUint(goroutineID(), 10)
+	}
+
+	if len(e.Fields) > 0 {
+		event.ContextMap = make(map[string]interface{}, len(e.Fields))
+		for _, f := range e.Fields {
+			event.ContextMap[f.Key] = f.Value
+		}
+	}
+
+	return json.Marshal(event)
+}
+
+// goroutineID parses the id of the calling goroutine from the
+// "goroutine 18 [running]:" header of its stack
+func goroutineID() uint64 {
+	var buf [64]byte
+	s := strings.TrimPrefix(string(buf[:runtime.Stack(buf[:], false)]), "goroutine ")
+	if i := strings.IndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+
+	id, _ := strconv.ParseUint(s, 10, 64)
+	return id
+}
@@ -0,0 +1,46 @@
+package applogger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logfmtEvent formats the entry as a logfmt line
+// time=2006-01-02T15:04:05.999999999Z07:00 level=info msg="message" key=value
+func logfmtEvent(e *LogEntry) string {
+	var b strings.Builder
+	b.WriteString("time=")
+	b.WriteString(e.Timestamp.Format(time.RFC3339Nano))
+	b.WriteString(" level=")
+	b.WriteString(strings.ToLower(textLevel(e)))
+	b.WriteString(" msg=")
+	b.WriteString(logfmtValue(e.Message))
+
+	for _, f := range e.Fields {
+		b.WriteString(" ")
+		b.WriteString(logfmtKey(f.Key))
+		b.WriteString("=")
+		b.WriteString(logfmtValue(fmt.Sprint(f.Value)))
+	}
+	return b.String()
+}
+
+// logfmtKey drops the characters a logfmt key can not hold
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes the value when it is empty or holds spaces, quotes or equal signs
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " =\"\t\r\n\\") {
+		return strconv.Quote(v)
+	}
+	return v
+}
@@ -1,14 +1,20 @@
 package applogger
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -17,14 +23,102 @@ import (
 
 // Logger it loads the config for logging
 type Logger struct {
-	// DisableColor default behavior is to log with no color
+	// DisableColor default behavior is to log with color
 	DisableColor bool
 	// DataTimeUTC default behavior is to log at local time
 	DataTimeUTC bool
+	// Format default behavior is to log in FormatText
+	Format Format
+	// CEF names the device in the header of FormatCEF events
+	CEF CEFConfig
+	// LogGoroutineID reports the goroutine as the thread of FormatLog4j2JSON events
+	LogGoroutineID bool
+	// LogFingerprint adds the Fingerprint of the entry to FormatJSON and
+	// FormatLog4j2JSON events, so aggregators can group the same entry from many hosts
+	LogFingerprint bool
+	// OnWriteError is called when a write fails, by default the error is printed to stderr
+	OnWriteError func(level int32, err error)
+	// CleanupFileExtensions are the log files LogDirectoryCleanup removes next to the
+	// date directories, nil uses .txt, .txt.gz, .log and .log.gz
+	CleanupFileExtensions []string
+	// OnCleanup is called with the path and the reason, age or count, before
+	// LogDirectoryCleanup or LogDirectoryCleanupByCount removes it, returning
+	// an error keeps the path
+	OnCleanup func(path, reason string) error
+	// CompressOldFiles makes LogDirectoryCleanup gzip the log files of the date
+	// directories it keeps, the files still being written are left alone
+	CompressOldFiles bool
+	// WriteFileHeader writes the start time, host and log settings at the top of new log files
+	WriteFileHeader bool
+	// SummaryInterval writes the number of entries per level every interval
+	SummaryInterval time.Duration
+	// SummaryEveryN writes the number of entries per level every n entries
+	SummaryEveryN int64
+	// PreallocateBytes reserves disk space for new log files on Linux, 0 disables it
+	PreallocateBytes int64
+	// AppVersion is written as app_version on every line when set
+	AppVersion string
+	// BuildCommit is written as build_commit on every line when set
+	BuildCommit string
+	// FileLogLevel is the level written to the log file when it differs from the
+	// console, e.g. LevelDebug to keep verbose files next to a concise console
+	FileLogLevel int32
+	// BasePathAbsolute resolves the baseFilePath of StartFile against the working
+	// directory once, so a later os.Chdir does not move the log files
+	BasePathAbsolute bool
+	// Async writes the lines from a background goroutine, Stop writes the queued lines
+	Async bool
+	// AsyncBufferSize is the number of lines queued before the back-pressure
+	// policy applies, 1024 by default
+	AsyncBufferSize int
+	// FlushInterval collects the queued lines and writes them every interval
+	// or once AsyncBufferSize lines are queued, 0 writes every line as it arrives
+	FlushInterval time.Duration
+	// AsyncBackPressure decides what logging does while the Async queue is
+	// full, BackPressureBlock by default
+	AsyncBackPressure BackPressurePolicy
+	// SampleRate writes only a random share, 0.0 to 1.0, of the calls,
+	// 0 and 1 write every call
+	SampleRate float64
+	// FileExtension is the extension of the log files, txt by default
+	FileExtension string
+	// FileNamePrefix is written before the date of the log file names, e.g. the hostname
+	FileNamePrefix string
+	// FileNameFunc names the log file created at t, e.g. app-2024-01-15T10.log,
+	// instead of FileNamePrefix, the date and FileExtension
+	FileNameFunc func(t time.Time) string
+	// RedactPatterns are replaced with [REDACTED] in the lines and string field
+	// values before they are written. Only the first group of a pattern with
+	// groups is replaced, e.g. password=(\S+) writes password=[REDACTED].
+	RedactPatterns []*regexp.Regexp
+	// CallerDepth is the number of frames skipped to find the caller written with
+	// every line, 2 by default. Packages wrapping the logger add their own frames.
+	CallerDepth int
+	// MaxBodyLogSize is the most body bytes DumpRequest and DumpResponse write,
+	// 64 KB by default
+	MaxBodyLogSize int
+	// ExitFunc ends the process once Fatal flushed the log files, os.Exit by
+	// default. Tests replace it to check the line written before the exit.
+	ExitFunc func(code int)
+	// PanicFunc is called with the message once Panic wrote it, the built-in
+	// panic by default. Tests replace it to log without unwinding.
+	PanicFunc func(v interface{})
+
+	levelMap map[int32]int32
+	every    uint64
+	calls    *uint64
+	buckets  map[int32]*tokenBucket
+	required []string
+	sampleN  uint64
+	sites    *sync.Map
+	fields   map[string]string
+
+	// app is the ApplicationLog the logger was started with
+	app *ApplicationLog
 }
 
 const (
-	// LevelDebug logs everything
+	// LevelDebug logs everything but Trace
 	LevelDebug int32 = 1
 
 	// LevelInfo logs Info, Warnings and Errors
@@ -35,6 +129,20 @@ const (
 
 	// LevelError logs just Errors
 	LevelError int32 = 8
+
+	// LevelTrace logs everything, including protocol traces finer than Debug
+	LevelTrace int32 = 16
+
+	// LevelFatal is the level of the lines written by Fatal before the process
+	// exits. They go to the Error destination, starting at LevelFatal logs
+	// the same as LevelError.
+	LevelFatal int32 = 32
+
+	// LevelVerbose is an alias of LevelDebug
+	LevelVerbose = LevelDebug
+
+	// LevelCritical is an alias of LevelError
+	LevelCritical = LevelError
 )
 
 // for coloring the std
@@ -54,126 +162,964 @@ const (
 )
 
 // ApplicationLog provides support to write to log files.
+// Every started Logger writes to its own ApplicationLog, so subsystems
+// can log at different levels to different outputs.
 type ApplicationLog struct {
-	LogLevel int32
-	Debug    *log.Logger
-	Info     *log.Logger
-	Warning  *log.Logger
-	Error    *log.Logger
-	File     *log.Logger
-	LogFile  *os.File
+	LogFile *os.File
+
+	logLevel   int32
+	traceLog   *log.Logger
+	debugLog   *log.Logger
+	infoLog    *log.Logger
+	warningLog *log.Logger
+	errorLog   *log.Logger
+
+	// config is the Logger that started the ApplicationLog
+	config *Logger
+
+	// files are the file writers of the levels, SetLevel keeps writing to them
+	files map[int32]io.Writer
+	// levelFiles are the files opened by StartMultiFile
+	levelFiles []*os.File
+	// fileWriter is the writer set with SetFileWriter
+	fileWriter io.WriteCloser
+	// logOut is the writer holding LogFile, DetectFileRename switches its file
+	logOut fileSwitcher
+	// children are the loggers added with AddChild, CascadeSetLevel sets their level
+	children []*Logger
+	// levelOverridden is set by SetLevel, CascadeSetLevel keeps the level
+	levelOverridden int32
+	// parents are the loggers added with ForwardTo
+	parents []*Logger
+	// exitFunc ends the process after Fatal, os.Exit when nil
+	exitFunc func(int)
+	// panicFunc panics after Panic, the built-in panic when nil
+	panicFunc func(interface{})
+
+	// started is set once the level loggers are set up by a Start function
+	started int32
+
+	format         Format
+	cef            CEFConfig
+	logGoroutineID bool
+	logFingerprint bool
+	onWriteError   func(level int32, err error)
+
+	mu         sync.RWMutex
+	transforms []Transform
+	buildInfo  []Field
+
+	stopped chan struct{}
+	wg      sync.WaitGroup
+
+	counts        levelCounts
+	summaryEveryN uint64
+	summarySince  time.Time
+
+	// writeMu is held for every write and by Lock, writeOwner is the goroutine holding Lock
+	writeMu    sync.Mutex
+	writeOwner uint64
+
+	// samplers holds the *callerSampler of the sampled call sites by sampleKey
+	samplers   sync.Map
+	sampling   int32
+	autoSample uint64
+
+	// fileLock is set by EnableFileLock
+	fileLock int32
+
+	// async is the queue of the Async lines
+	async *asyncQueue
+
+	// tees are the writers added with Tee, replaced on every change
+	teeMu sync.Mutex
+	tees  []*tee
+
+	// hooks are the hooks added with AddHook, replaced on every change
+	hookMu sync.Mutex
+	hooks  []Hook
+
+	// latency is the histogram of WriteLatencyHandler, see writeLatency
+	latencyOnce sync.Once
+	latency     *metricVec
+}
+
+// reasons passed to OnCleanup, for paths older than daysToKeep and for
+// directories beyond maxDirs
+const (
+	cleanupReasonAge   = "age"
+	cleanupReasonCount = "count"
+)
+
+// defaultCallerDepth is the CallerDepth of a logger called directly
+const defaultCallerDepth = 2
+
+// defaultCleanupFileExtensions is used when Logger.CleanupFileExtensions is nil
+var defaultCleanupFileExtensions = []string{".txt", ".txt.gz", ".log", ".log.gz"}
+
+// std is the ApplicationLog started last. Loggers that were not started
+// and the package level functions write to it.
+var (
+	stdMu sync.RWMutex
+	std   = &ApplicationLog{config: &Logger{}}
+)
+
+// stdBuildInfo is the build info of SetBuildInfo, the ApplicationLogs started
+// afterwards write it as well
+var stdBuildInfo struct {
+	sync.RWMutex
+	fields []Field
+}
+
+func init() {
+	std.config.app = std
+}
+
+// Default returns the ApplicationLog started last
+func Default() *ApplicationLog {
+	stdMu.RLock()
+	defer stdMu.RUnlock()
+	return std
+}
+
+// Discard returns a logger that writes nothing, e.g. for tests of functions
+// taking a *Logger. It has no file, starts no goroutines and does not
+// replace the ApplicationLog of the package level functions.
+func Discard() *Logger {
+	l := &Logger{}
+	l.app = &ApplicationLog{
+		config:     l,
+		traceLog:   log.New(ioutil.Discard, "", 0),
+		debugLog:   log.New(ioutil.Discard, "", 0),
+		infoLog:    log.New(ioutil.Discard, "", 0),
+		warningLog: log.New(ioutil.Discard, "", 0),
+		errorLog:   log.New(ioutil.Discard, "", 0),
+		started:    1,
+	}
+	return l
+}
+
+// instance returns the ApplicationLog the logger writes to
+func (l *Logger) instance() *ApplicationLog {
+	if l.app != nil && atomic.LoadInt32(&l.app.started) == 1 {
+		return l.app
+	}
+	return Default()
+}
+
+// pending returns the ApplicationLog of the logger, creating it when the logger
+// was not started yet, so the hooks, transforms and goroutines added before
+// Start belong to the logger instead of the default ApplicationLog
+func (l *Logger) pending() *ApplicationLog {
+	if l.app == nil {
+		l.app = &ApplicationLog{config: l}
+	}
+	return l.app
+}
+
+// start binds the logger to its ApplicationLog, creating it on the first
+// start, and makes it the default.
+func (l *Logger) start() *ApplicationLog {
+	l.pending()
+
+	stdMu.Lock()
+	std = l.app
+	stdMu.Unlock()
+
+	return l.app
 }
 
-// log maintains a pointer to a singleton for the logging system.
-var logger ApplicationLog
+// output writes msg at the given level in the configured format after
+// running it through the registered transforms. levelName is empty unless
+// the call was made through a level alias.
+// calldepth is counted from the caller of output, the same as log.Output.
+func (a *ApplicationLog) output(level int32, levelName string, calldepth int, msg string, fields ...Field) error {
+	entry := a.entry(level, levelName, calldepth+1, msg, fields...)
+	if entry == nil {
+		return nil
+	}
+	return a.emit(entry, calldepth+1)
+}
+
+// entry builds the entry written by output, it returns nil when the
+// entry is sampled out or dropped by a transform
+func (a *ApplicationLog) entry(level int32, levelName string, calldepth int, msg string, fields ...Field) *LogEntry {
+	file, line, function := caller(calldepth)
+	if !a.sampled(file, line) {
+		return nil
+	}
+
+	a.mu.RLock()
+	transforms := a.transforms
+	buildInfo := a.buildInfo
+	a.mu.RUnlock()
+
+	entry := &LogEntry{
+		Level:     level,
+		LevelName: levelName,
+		Timestamp: time.Now(),
+		Caller:    filepath.Base(file) + ":" + strconv.Itoa(line),
+		Function:  function,
+		Message:   strings.TrimSuffix(msg, "\n"),
+		Fields:    withBuildInfo(fields, buildInfo),
+	}
+
+	for _, transform := range transforms {
+		if entry = transform(entry); entry == nil {
+			return nil
+		}
+	}
+	return entry
+}
+
+// emit writes the entry, counts it for the summary and forwards it to the
+// parents of ForwardTo
+func (a *ApplicationLog) emit(entry *LogEntry, calldepth int) error {
+	err := a.writeEntry(entry, calldepth+1)
+	if err != nil {
+		a.writeFailed(entry.Level, err)
+	} else {
+		a.countEntry(entry.Level)
+	}
+
+	a.forward(entry, calldepth+1)
+	return err
+}
+
+// caller returns the file, line and function of the caller at calldepth,
+// counted from the caller of output
+func caller(calldepth int) (string, int, string) {
+	pc, file, line, ok := runtime.Caller(calldepth + 1)
+	if !ok {
+		return "???", 0, ""
+	}
+
+	var function string
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		function = fn.Name()
+	}
+	return file, line, function
+}
+
+// writeEntry formats the entry and writes it to the level logger
+func (a *ApplicationLog) writeEntry(entry *LogEntry, calldepth int) error {
+	defer a.lockWrite()()
+
+	switch a.format {
+	case FormatCEF:
+		return a.writeLine(entry.Level, cefEvent(entry, a.cef))
+	case FormatLog4j2JSON:
+		line, err := log4j2Event(entry, a.logGoroutineID, a.logFingerprint)
+		if err != nil {
+			return err
+		}
+		return a.writeLine(entry.Level, string(line))
+	case FormatJSON:
+		line, err := jsonEvent(entry, a.logFingerprint)
+		if err != nil {
+			return err
+		}
+		return a.writeLine(entry.Level, string(line))
+	case FormatLogfmt:
+		return a.writeLine(entry.Level, logfmtEvent(entry))
+	default:
+		return a.levelLogger(entry.Level).Output(calldepth+1, entry.Message+textFields(entry.Fields))
+	}
+}
+
+// writeLine writes an already formatted line to the writer of the level logger
+func (a *ApplicationLog) writeLine(level int32, line string) error {
+	_, err := io.WriteString(a.levelLogger(level).Writer(), line+"\n")
+	return err
+}
+
+// writeLine writes an already formatted line at level, applying the options of the logger
+func (l *Logger) writeLine(level int32, line string) {
+	if !l.allow() {
+		return
+	}
+
+	level = l.mapLevel(level)
+	if !l.throttle(level, 1) {
+		return
+	}
+
+	if len(l.RedactPatterns) > 0 {
+		line = redact(line, l.RedactPatterns)
+	}
+
+	a := l.instance()
+	unlock := a.lockWrite()
+	err := a.writeLine(level, line)
+	unlock()
+
+	if err != nil {
+		a.writeFailed(level, err)
+	}
+}
+
+// writeFailed reports a failed write to OnWriteError, or to stderr when it is not set
+func (a *ApplicationLog) writeFailed(level int32, err error) {
+	if a.onWriteError != nil {
+		a.onWriteError(level, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "applogger: write failed: %s\n", err)
+}
+
+// levelLogger returns the logger that writes the given level.
+func (a *ApplicationLog) levelLogger(level int32) *log.Logger {
+	switch level {
+	case LevelTrace:
+		return a.traceLog
+	case LevelDebug:
+		return a.debugLog
+	case LevelInfo:
+		return a.infoLog
+	case LevelWarn:
+		return a.warningLog
+	default:
+		return a.errorLog
+	}
+}
+
+// background runs fn in a goroutine that Stop waits for.
+// fn must return once stopped is closed.
+func (a *ApplicationLog) background(fn func(stopped <-chan struct{})) {
+	a.mu.Lock()
+	if a.stopped == nil {
+		a.stopped = make(chan struct{})
+	}
+	stopped := a.stopped
+	a.wg.Add(1)
+	a.mu.Unlock()
+
+	go func() {
+		defer a.wg.Done()
+		fn(stopped)
+	}()
+}
+
+// stopBackground signals the background goroutines to stop and waits for them.
+func (a *ApplicationLog) stopBackground() {
+	a.mu.Lock()
+	if a.stopped != nil {
+		close(a.stopped)
+		a.stopped = nil
+	}
+	a.mu.Unlock()
+
+	a.wg.Wait()
+}
 
 // Start initializes ApplicationLog and only displays the specified logging level.
-func (l *Logger) Start(logLevel int32) {
+// A logLevel of 0 uses the level named by the APPLOGGER_LEVEL environment
+// variable, e.g. APPLOGGER_LEVEL=debug; a level passed in always wins. The
+// other Start functions do the same.
+func (l *Logger) Start(logLevel int32) *ApplicationLog {
+	a := l.start()
 	l.turnOnLogging(logLevel, nil)
+	l.startSummary()
+	return a
+}
+
+// MustStart is Start for symmetry with MustStartFile, turning the logging on
+// for the console cannot fail.
+func (l *Logger) MustStart(logLevel int32) *ApplicationLog {
+	return l.Start(logLevel)
+}
+
+// MustStartFile is StartFile that panics with the error when the log file
+// cannot be created instead of exiting the process, so the failure can be
+// recovered from, e.g. in tests.
+func (l *Logger) MustStartFile(logLevel int32, baseFilePath string, daysToKeep int) *ApplicationLog {
+	a, err := l.TryStartFile(logLevel, baseFilePath, daysToKeep)
+	if err != nil {
+		panic(err)
+	}
+	return a
 }
 
 // StartFile initializes tracelog and only displays the specified logging level
-// and creates a file to capture writes.
-func (l *Logger) StartFile(logLevel int32, baseFilePath string, daysToKeep int) {
+// and creates a file to capture writes. Calling it again, e.g. from init and
+// main, continues in a new file and closes the previous one; Stop closes the last.
+func (l *Logger) StartFile(logLevel int32, baseFilePath string, daysToKeep int) *ApplicationLog {
+	return l.StartFileWithRotation(logLevel, baseFilePath, daysToKeep, 0)
+}
+
+// TryStartFile is StartFile that returns the error when the directory or the
+// file cannot be created instead of exiting the process. The logging is then
+// left as it was.
+func (l *Logger) TryStartFile(logLevel int32, baseFilePath string, daysToKeep int) (*ApplicationLog, error) {
+	return l.startFile(logLevel, baseFilePath, daysToKeep, 0, nil)
+}
+
+// StartFileWithRotation is StartFile that continues in a new file with an
+// incremented sequence suffix once the file grows past maxFileSizeMB.
+// The files stay in the directory of the day StartFileWithRotation was called.
+// A maxFileSizeMB of 0 turns the size rotation off.
+func (l *Logger) StartFileWithRotation(logLevel int32, baseFilePath string, daysToKeep int, maxFileSizeMB int64) *ApplicationLog {
+	return exitOnError(l.startFile(logLevel, baseFilePath, daysToKeep, maxFileSizeMB, nil))
+}
+
+// StartFileAndWriter is StartFile that also writes every line to extra,
+// e.g. a pipe to an external process. extra is not closed by Stop.
+func (l *Logger) StartFileAndWriter(logLevel int32, baseFilePath string, daysToKeep int, extra io.Writer) *ApplicationLog {
+	return exitOnError(l.startFile(logLevel, baseFilePath, daysToKeep, 0, extra))
+}
+
+// exitOnError exits the process with err, the Start functions do so when the
+// log file cannot be created
+func exitOnError(a *ApplicationLog, err error) *ApplicationLog {
+	if err != nil {
+		log.Fatalf("%s\n", err)
+	}
+	return a
+}
+
+// startFile creates the log file and turns the logging on, it returns the
+// error when the file cannot be created.
+func (l *Logger) startFile(logLevel int32, baseFilePath string, daysToKeep int, maxFileSizeMB int64, extra io.Writer) (*ApplicationLog, error) {
+	logf, baseFilePath, err := l.createLogFile(baseFilePath, dailyDirLayout)
+	if err != nil {
+		return nil, err
+	}
+
+	a := l.start()
+	var out fileSwitcher = &logFile{a: a, file: logf}
+	if maxFileSizeMB > 0 {
+		out = &rotatingFile{
+			l:        l,
+			logLevel: envLevel(logLevel),
+			maxBytes: maxFileSizeMB * 1024 * 1024,
+			base:     strings.TrimSuffix(logf.Name(), filepath.Ext(logf.Name())),
+			ext:      filepath.Ext(logf.Name()),
+			file:     logf,
+		}
+	}
+	l.startOnFile(a, logLevel, logf, out, extra)
+
+	// Cleanup any existing directories
+	l.LogDirectoryCleanup(baseFilePath, daysToKeep)
+	return a, nil
+}
+
+// The directories of the log files under baseFilePath, as time layouts
+const (
+	dailyDirLayout  = "2006-01-02"
+	hourlyDirLayout = "2006-01-02/15"
+)
+
+// createLogFile creates the log file in the directory named after the current
+// time with dirLayout under baseFilePath, it returns the file and baseFilePath
+// made absolute when BasePathAbsolute is set.
+func (l *Logger) createLogFile(baseFilePath string, dirLayout string) (*os.File, string, error) {
 	baseFilePath = strings.TrimRight(baseFilePath, "/")
-	currentDate := time.Now().UTC()
-	dateDirectory := time.Now().UTC().Format("2006-01-02")
-	dateFile := currentDate.Format("2006-01-02T15-04-05")
+	if l.BasePathAbsolute {
+		absPath, err := filepath.Abs(baseFilePath)
+		if err != nil {
+			return nil, "", fmt.Errorf("main : Start : Failed to Resolve log directory : %s : %s", baseFilePath, err)
+		}
+		baseFilePath = absPath
+	}
+
+	currentDate := l.now()
+	dateDirectory := currentDate.Format(dirLayout)
 
 	filePath := fmt.Sprintf("%s/%s/", baseFilePath, dateDirectory)
-	fileName := strings.Replace(fmt.Sprintf("%s.txt", dateFile), " ", "-", -1)
+	fileName := l.fileName(currentDate)
 
 	err := os.MkdirAll(filePath, os.ModePerm)
 	if err != nil {
-		log.Fatalf("main : Start : Failed to Create log directory : %s : %s\n", filePath, err)
+		return nil, "", fmt.Errorf("main : Start : Failed to Create log directory : %s : %s", filePath, err)
 	}
 
 	logf, err := os.Create(fmt.Sprintf("%s%s", filePath, fileName))
 	if err != nil {
-		log.Fatalf("main : Start : Failed to Create log file : %s : %s\n", fileName, err)
+		return nil, "", fmt.Errorf("main : Start : Failed to Create log file : %s : %s", fileName, err)
+	}
+	return logf, baseFilePath, nil
+}
+
+// timeNow is the clock of now, replaced in tests
+var timeNow = time.Now
+
+// now is the time the log files and directories are named after, UTC when
+// DataTimeUTC is set so they match the timestamps of the lines
+func (l *Logger) now() time.Time {
+	if l.DataTimeUTC {
+		return timeNow().UTC()
+	}
+	return timeNow()
+}
+
+// fileName names the log file created at t, by default <prefix><datetime>.<ext>
+func (l *Logger) fileName(t time.Time) string {
+	if l.FileNameFunc != nil {
+		return l.FileNameFunc(t)
+	}
+
+	ext := strings.TrimPrefix(l.FileExtension, ".")
+	if ext == "" {
+		ext = "txt"
+	}
+	return fmt.Sprintf("%s%s.%s", l.FileNamePrefix, t.Format("2006-01-02T15-04-05"), ext)
+}
+
+// startOnFile turns the logging on for logf written through out, and extra
+func (l *Logger) startOnFile(a *ApplicationLog, logLevel int32, logf *os.File, out fileSwitcher, extra io.Writer) {
+	var w io.Writer = &lockedFile{w: out, a: a}
+	if extra != nil {
+		w = io.MultiWriter(w, extra)
 	}
 
 	// Turn the logging on
-	l.turnOnLogging(logLevel, logf)
+	l.turnOnLogging(logLevel, w)
+	a.mu.Lock()
+	previous := a.LogFile
+	a.LogFile = logf
+	a.logOut = out
+	a.mu.Unlock()
+	l.preallocate(logf)
+
+	// The logger was started on a file before, it is no longer written to
+	if previous != nil && previous != logf {
+		if err := previous.Close(); err != nil {
+			l.Warning("StartFile : Failed to Close previous log file [%s] : %s", previous.Name(), err)
+		}
+	}
+	l.startSummary()
 
-	// Cleanup any existing directories
-	l.LogDirectoryCleanup(baseFilePath, daysToKeep)
+	if l.BasePathAbsolute {
+		l.Info("StartFile : Logging to [%s]", logf.Name())
+	}
+}
+
+// StartWriter initializes ApplicationLog like StartFile but writes to w instead
+// of a file, e.g. a bytes.Buffer in tests. Stop does not close w.
+func (l *Logger) StartWriter(logLevel int32, w io.Writer) *ApplicationLog {
+	// Turn the logging on
+	a := l.start()
+	l.turnOnLogging(logLevel, w)
+	l.startSummary()
+	return a
+}
+
+// SetFileWriter initializes ApplicationLog like StartFile but writes to w instead
+// of a file, e.g. a remote file or a writer that encrypts. Stop closes w and
+// LogDirectoryCleanup does nothing while it is in use.
+func (l *Logger) SetFileWriter(logLevel int32, w io.WriteCloser) *ApplicationLog {
+	// Turn the logging on
+	a := l.start()
+	l.turnOnLogging(logLevel, w)
+	a.fileWriter = w
+	l.startSummary()
+	return a
 }
 
 // Stop will release resources and shutdown all processing.
+// It does nothing for a logger that was never started.
 func (l *Logger) Stop() error {
+	// The goroutines added before Start belong to the logger as well
+	a := l.app
+	if a == nil {
+		return nil
+	}
+
 	l.Started("Stop")
 
-	var err error
-	if logger.LogFile != nil {
-		l.Debug("Stop", "Closing File")
-		err = logger.LogFile.Close()
+	// Stop the background goroutines before the file is closed
+	a.stopBackground()
+
+	a.mu.Lock()
+	files := a.levelFiles
+	if a.LogFile != nil {
+		files = append([]*os.File{a.LogFile}, files...)
+	}
+	a.LogFile = nil
+	a.logOut = nil
+	a.levelFiles = nil
+	fileWriter := a.fileWriter
+	a.fileWriter = nil
+	a.mu.Unlock()
+
+	for _, f := range files {
+		l.Debug("Stop : Closing File [%s]", f.Name())
 	}
 
+	if fileWriter != nil {
+		l.Debug("Stop : Closing File Writer")
+	}
+
+	// The files are closed last so nothing is written to them afterwards
 	l.Completed("Stop")
+
+	var err error
+	for _, f := range files {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	if fileWriter != nil {
+		if cerr := fileWriter.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
 	return err
 }
 
-// LogLevel returns the configured logging level.
+// ErrUnknownLevel is returned for a level that is not one of the level constants
+var ErrUnknownLevel = errors.New("applogger: unknown level")
+
+// ErrInvalidInterval is returned for an interval of 0 or less
+var ErrInvalidInterval = errors.New("applogger: interval must be positive")
+
+// SetOutput redirects the lines of one level to w, e.g. the Errors to an alerting
+// sidecar, leaving the other levels as they are. The lines still go through
+// the hooks, the tees and the Async queue. It does nothing before Start.
+func (l *Logger) SetOutput(level int32, w io.Writer) error {
+	a := l.instance()
+
+	var logger *log.Logger
+	switch level {
+	case LevelTrace:
+		logger = a.traceLog
+	case LevelDebug:
+		logger = a.debugLog
+	case LevelInfo:
+		logger = a.infoLog
+	case LevelWarn:
+		logger = a.warningLog
+	case LevelError, LevelFatal:
+		logger, level = a.errorLog, LevelError
+	default:
+		return ErrUnknownLevel
+	}
+	if logger == nil {
+		return nil
+	}
+
+	w = a.teeWriter(a.hookWriter(level, w))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if q := a.async; q != nil && !q.isClosed() {
+		w = q.writer(level, w)
+	}
+	logger.SetOutput(w)
+	return nil
+}
+
+// LogLevel returns the configured logging level of the default ApplicationLog.
 func LogLevel() int32 {
-	return atomic.LoadInt32(&logger.LogLevel)
+	return Default().LogLevel()
+}
+
+// LogLevel returns the configured logging level.
+func (a *ApplicationLog) LogLevel() int32 {
+	return atomic.LoadInt32(&a.logLevel)
+}
+
+// Stop will release resources and shutdown all processing.
+func (a *ApplicationLog) Stop() error {
+	return a.config.Stop()
+}
+
+// WithLevelMapping returns a copy of the logger that writes the calls made
+// at level from at level to, e.g. WithLevelMapping(LevelDebug, LevelInfo)
+// makes the Debug calls of a subsystem visible without changing the global level.
+func (l *Logger) WithLevelMapping(from, to int32) *Logger {
+	derived := *l
+	derived.levelMap = make(map[int32]int32, len(l.levelMap)+1)
+	for k, v := range l.levelMap {
+		derived.levelMap[k] = v
+	}
+	derived.levelMap[from] = to
+	return &derived
+}
+
+// Every returns a copy of the logger that only writes every nth call made
+// through it. The count is kept per returned logger, not per call site.
+func (l *Logger) Every(n int) *Logger {
+	derived := *l
+	derived.every = 0
+	derived.calls = nil
+	if n > 1 {
+		derived.every = uint64(n)
+		derived.calls = new(uint64)
+	}
+	return &derived
+}
+
+// write applies the options of the logger before handing msg to output.
+// calldepth is counted from the caller of write.
+func (l *Logger) write(level int32, calldepth int, msg string, fields ...Field) error {
+	return l.writeAs(level, "", calldepth+1, msg, fields...)
+}
+
+// writeAs is write for the level aliases, levelName is kept unless the level is mapped
+func (l *Logger) writeAs(level int32, levelName string, calldepth int, msg string, fields ...Field) error {
+	if !l.allow() {
+		return nil
+	}
+	calldepth = l.callerDepth(calldepth)
+
+	mapped := l.mapLevel(level)
+	if mapped != level {
+		levelName = ""
+	}
+
+	if !l.throttle(mapped, calldepth+1) {
+		return nil
+	}
+
+	return l.outputAs(mapped, levelName, calldepth+1, msg, fields...)
+}
+
+// outputAs adds the fields of WithFields to the line, redacts it and writes it.
+// Fatal and Panic call it directly so their lines are never sampled out.
+func (l *Logger) outputAs(level int32, levelName string, calldepth int, msg string, fields ...Field) error {
+	if l.Format.isStructured() {
+		fields = l.withFields(fields)
+	} else {
+		msg = l.withFieldsMessage(msg)
+	}
+	msg, fields = l.redacted(msg, fields)
+	if len(l.required) == 0 {
+		return l.instance().output(level, levelName, calldepth+1, msg, fields...)
+	}
+	return l.outputRequired(level, levelName, calldepth+1, msg, fields...)
+}
+
+// redacted applies the RedactPatterns to the message and the fields
+func (l *Logger) redacted(msg string, fields []Field) (string, []Field) {
+	if len(l.RedactPatterns) == 0 {
+		return msg, fields
+	}
+	return redact(msg, l.RedactPatterns), redactFields(fields, l.RedactPatterns)
+}
+
+// WithCallerDepth returns a copy of the logger skipping depth frames to find
+// the caller, e.g. 3 for a logger called through a wrapper function
+func (l *Logger) WithCallerDepth(depth int) *Logger {
+	derived := *l
+	derived.CallerDepth = depth
+	return &derived
+}
+
+// callerDepth adds the frames of CallerDepth to the calldepth of a direct call
+func (l *Logger) callerDepth(calldepth int) int {
+	if l.CallerDepth <= 0 {
+		return calldepth
+	}
+	return calldepth + l.CallerDepth - defaultCallerDepth
+}
+
+// allow reports whether the options of the logger let the current call be written
+func (l *Logger) allow() bool {
+	if l.every > 1 && atomic.AddUint64(l.calls, 1)%l.every != 0 {
+		return false
+	}
+	if l.SampleRate > 0 && l.SampleRate < 1 && sampleFloat() >= l.SampleRate {
+		return false
+	}
+	return true
+}
+
+// mapLevel returns the level a call made at level is written at
+func (l *Logger) mapLevel(level int32) int32 {
+	if to, ok := l.levelMap[level]; ok {
+		return to
+	}
+	return level
 }
 
 // turnOnLogging configures the logging writers.
 func (l *Logger) turnOnLogging(logLevel int32, fileHandle io.Writer) {
+	var files map[int32]io.Writer
+	if fileHandle != nil {
+		files = map[int32]io.Writer{
+			LevelTrace: fileHandle,
+			LevelDebug: fileHandle,
+			LevelInfo:  fileHandle,
+			LevelWarn:  fileHandle,
+			LevelError: fileHandle,
+		}
+	}
+	l.turnOnLevelLogging(logLevel, files)
+}
+
+// turnOnLevelLogging configures the logging writers, each level is also
+// written to its file in files when it has one.
+func (l *Logger) turnOnLevelLogging(logLevel int32, files map[int32]io.Writer) {
+	logLevel = envLevel(logLevel)
+	a := l.pending()
+	handles := l.levelHandles(a, logLevel, files)
+	traceHandle := handles[LevelTrace]
+	debugHandle := handles[LevelDebug]
+	infoHandle := handles[LevelInfo]
+	warnHandle := handles[LevelWarn]
+	errorHandle := handles[LevelError]
+
+	// Levels sharing a file get a single header
+	var headerFiles []io.Writer
+	for _, level := range []int32{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		if h := files[level]; h != nil && !containsWriter(headerFiles, h) {
+			headerFiles = append(headerFiles, h)
+		}
+	}
+
+	if l.WriteFileHeader {
+		for _, h := range headerFiles {
+			l.writeFileHeader(h, logLevel)
+		}
+	}
+
+	if l.Format == FormatW3CExtended {
+		header := w3cHeader(time.Now())
+		if levelOn(LevelWarn, logLevel) {
+			io.WriteString(os.Stdout, header)
+		}
+		if levelOn(LevelError, logLevel) {
+			io.WriteString(os.Stderr, header)
+		}
+		for _, h := range headerFiles {
+			io.WriteString(h, header)
+		}
+	}
+
+	traceHandle = a.hookWriter(LevelTrace, traceHandle)
+	debugHandle = a.hookWriter(LevelDebug, debugHandle)
+	infoHandle = a.hookWriter(LevelInfo, infoHandle)
+	warnHandle = a.hookWriter(LevelWarn, warnHandle)
+	errorHandle = a.hookWriter(LevelError, errorHandle)
+
+	traceHandle = a.teeWriter(traceHandle)
+	debugHandle = a.teeWriter(debugHandle)
+	infoHandle = a.teeWriter(infoHandle)
+	warnHandle = a.teeWriter(warnHandle)
+	errorHandle = a.teeWriter(errorHandle)
+
+	if l.Async {
+		q := a.startAsync(l.AsyncBufferSize, l.FlushInterval, l.AsyncBackPressure)
+		traceHandle = q.writer(LevelTrace, traceHandle)
+		debugHandle = q.writer(LevelDebug, debugHandle)
+		infoHandle = q.writer(LevelInfo, infoHandle)
+		warnHandle = q.writer(LevelWarn, warnHandle)
+		errorHandle = q.writer(LevelError, errorHandle)
+	}
+
+	timestamp := dateTimeUTC(log.Ldate|log.Ltime|log.Lshortfile, l.DataTimeUTC)
+
+	a.traceLog = log.New(traceHandle, l.prefix("TRACE: ", colorDarkGray), timestamp)
+	a.debugLog = log.New(debugHandle, l.prefix("DEBUG: ", colorBlack), timestamp)
+	a.infoLog = log.New(infoHandle, l.prefix("INFO: ", colorBlue), timestamp)
+	a.warningLog = log.New(warnHandle, l.prefix("WARNING: ", colorYellow), timestamp)
+	a.errorLog = log.New(errorHandle, l.prefix("ERROR: ", colorRed), timestamp)
+
+	a.mu.Lock()
+	a.files = files
+	a.exitFunc = l.ExitFunc
+	a.panicFunc = l.PanicFunc
+	a.mu.Unlock()
+
+	a.format = l.Format
+	a.cef = l.CEF
+	a.logGoroutineID = l.LogGoroutineID
+	a.logFingerprint = l.LogFingerprint
+	a.onWriteError = l.OnWriteError
+
+	// Keep the build info of SetBuildInfo unless the logger sets its own
+	if l.AppVersion != "" || l.BuildCommit != "" {
+		a.setBuildInfo(l.AppVersion, l.BuildCommit, "")
+	} else {
+		a.inheritBuildInfo()
+	}
+
+	atomic.StoreInt32(&a.logLevel, logLevel)
+	atomic.StoreInt32(&a.started, 1)
+}
+
+// levelHandles returns the writers of the levels logged at logLevel: the console
+// and the file of the level in files, ioutil.Discard for the levels not logged
+func (l *Logger) levelHandles(a *ApplicationLog, logLevel int32, files map[int32]io.Writer) map[int32]io.Writer {
+	traceHandle := ioutil.Discard
 	debugHandle := ioutil.Discard
 	infoHandle := ioutil.Discard
 	warnHandle := ioutil.Discard
 	errorHandle := ioutil.Discard
 
-	if logLevel&LevelDebug != 0 {
+	if levelOn(LevelTrace, logLevel) {
+		traceHandle = os.Stdout
+	}
+
+	if levelOn(LevelDebug, logLevel) {
 		debugHandle = os.Stdout
-		infoHandle = os.Stdout
-		warnHandle = os.Stdout
-		errorHandle = os.Stderr
 	}
 
-	if logLevel&LevelInfo != 0 {
+	if levelOn(LevelInfo, logLevel) {
 		infoHandle = os.Stdout
-		warnHandle = os.Stdout
-		errorHandle = os.Stderr
 	}
 
-	if logLevel&LevelWarn != 0 {
+	if levelOn(LevelWarn, logLevel) {
 		warnHandle = os.Stdout
-		errorHandle = os.Stderr
 	}
 
-	if logLevel&LevelError != 0 {
+	if levelOn(LevelError, logLevel) {
 		errorHandle = os.Stderr
 	}
 
-	if fileHandle != nil {
-		if debugHandle == os.Stdout {
-			debugHandle = io.MultiWriter(fileHandle, debugHandle)
-		}
+	// Every destination is measured on its own for WriteLatencyHandler
+	traceHandle = a.latencyWriter(LevelTrace, "console", traceHandle)
+	debugHandle = a.latencyWriter(LevelDebug, "console", debugHandle)
+	infoHandle = a.latencyWriter(LevelInfo, "console", infoHandle)
+	warnHandle = a.latencyWriter(LevelWarn, "console", warnHandle)
+	errorHandle = a.latencyWriter(LevelError, "console", errorHandle)
+
+	// The files log from FileLogLevel when it is set, e.g. Debug to the file
+	// and Warnings to the console
+	fileLevel := logLevel
+	if l.FileLogLevel != 0 {
+		fileLevel = l.FileLogLevel
+	}
 
-		if infoHandle == os.Stdout {
-			infoHandle = io.MultiWriter(fileHandle, infoHandle)
-		}
+	if h := files[LevelTrace]; h != nil && levelOn(LevelTrace, fileLevel) {
+		traceHandle = withFile(a.latencyWriter(LevelTrace, writerType(h), h), traceHandle)
+	}
 
-		if warnHandle == os.Stdout {
-			warnHandle = io.MultiWriter(fileHandle, warnHandle)
-		}
+	if h := files[LevelDebug]; h != nil && levelOn(LevelDebug, fileLevel) {
+		debugHandle = withFile(a.latencyWriter(LevelDebug, writerType(h), h), debugHandle)
+	}
 
-		if errorHandle == os.Stderr {
-			errorHandle = io.MultiWriter(fileHandle, errorHandle)
-		}
+	if h := files[LevelInfo]; h != nil && levelOn(LevelInfo, fileLevel) {
+		infoHandle = withFile(a.latencyWriter(LevelInfo, writerType(h), h), infoHandle)
 	}
 
-	timestamp := dateTimeUTC(log.Ldate|log.Ltime|log.Lshortfile, l.DataTimeUTC)
+	if h := files[LevelWarn]; h != nil && levelOn(LevelWarn, fileLevel) {
+		warnHandle = withFile(a.latencyWriter(LevelWarn, writerType(h), h), warnHandle)
+	}
 
-	logger.Debug = log.New(debugHandle, colorize("DEBUG: ", colorBlack, l.DisableColor), timestamp)
-	logger.Info = log.New(infoHandle, colorize("INFO: ", colorBlue, l.DisableColor), timestamp)
-	logger.Warning = log.New(warnHandle, colorize("WARNING: ", colorYellow, l.DisableColor), timestamp)
-	logger.Error = log.New(errorHandle, colorize("ERROR: ", colorRed, l.DisableColor), timestamp)
+	if h := files[LevelError]; h != nil && levelOn(LevelError, fileLevel) {
+		errorHandle = withFile(a.latencyWriter(LevelError, writerType(h), h), errorHandle)
+	}
 
-	atomic.StoreInt32(&logger.LogLevel, logLevel)
+	return map[int32]io.Writer{
+		LevelTrace: traceHandle,
+		LevelDebug: debugHandle,
+		LevelInfo:  infoHandle,
+		LevelWarn:  warnHandle,
+		LevelError: errorHandle,
+	}
+}
+
+// withFile adds the file h to the console writer of a level
+func withFile(h, console io.Writer) io.Writer {
+	if console == ioutil.Discard {
+		return h
+	}
+	return io.MultiWriter(h, console)
 }
 
 // LogDirectoryCleanup performs all the directory cleanup and maintenance.
@@ -181,6 +1127,12 @@ func (l *Logger) LogDirectoryCleanup(baseFilePath string, daysToKeep int) {
 
 	l.Startedf("LogDirectoryCleanup", "BaseFilePath[%s] DaysToKeep[%d]", baseFilePath, daysToKeep)
 
+	// There is no local directory behind a writer set with SetFileWriter.
+	if l.instance().fileWriter != nil {
+		l.Completedf("LogDirectoryCleanup", "Skipped, a file writer is in use")
+		return
+	}
+
 	// Get a list of existing directories.
 	fileInfos, err := ioutil.ReadDir(baseFilePath)
 	if err != nil {
@@ -189,13 +1141,14 @@ func (l *Logger) LogDirectoryCleanup(baseFilePath string, daysToKeep int) {
 	}
 
 	// Create the date to compare for directories to remove.
-	currentDate := time.Now().UTC()
-	compareDate := time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day()-daysToKeep, 0, 0, 0, 0, time.UTC)
+	currentDate := l.now()
+	compareDate := time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day()-daysToKeep, 0, 0, 0, 0, currentDate.Location())
 
-	l.Debug("LogDirectoryCleanup", "CompareDate[%v]", compareDate)
+	l.Debug("LogDirectoryCleanup : CompareDate[%v]", compareDate)
 
 	for _, fileInfo := range fileInfos {
 		if fileInfo.IsDir() == false {
+			l.logFileCleanup(baseFilePath, fileInfo, compareDate)
 			continue
 		}
 
@@ -204,19 +1157,19 @@ func (l *Logger) LogDirectoryCleanup(baseFilePath string, daysToKeep int) {
 
 		year, err := strconv.Atoi(parts[0])
 		if err != nil {
-			l.Errorf("LogDirectoryCleanup", err, "Attempting To Convert Directory [%s]", fileInfo.Name())
+			l.ErrorWith(err, "LogDirectoryCleanup : Attempting To Convert Directory [%s] :", fileInfo.Name())
 			continue
 		}
 
 		month, err := strconv.Atoi(parts[1])
 		if err != nil {
-			l.Errorf("LogDirectoryCleanup", err, "Attempting To Convert Directory [%s]", fileInfo.Name())
+			l.ErrorWith(err, "LogDirectoryCleanup : Attempting To Convert Directory [%s] :", fileInfo.Name())
 			continue
 		}
 
 		day, err := strconv.Atoi(parts[2])
 		if err != nil {
-			l.Errorf("LogDirectoryCleanup", err, "Attempting To Convert Directory [%s]", fileInfo.Name())
+			l.ErrorWith(err, "LogDirectoryCleanup : Attempting To Convert Directory [%s] :", fileInfo.Name())
 			continue
 		}
 
@@ -224,23 +1177,33 @@ func (l *Logger) LogDirectoryCleanup(baseFilePath string, daysToKeep int) {
 		fullFileName := fmt.Sprintf("%s/%s", baseFilePath, fileInfo.Name())
 
 		// Create a time type from the directory name.
-		directoryDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		directoryDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, currentDate.Location())
 
 		// Compare the dates and convert to days.
 		daysOld := int(compareDate.Sub(directoryDate).Hours() / 24)
 
-		l.Debug("LogDirectoryCleanup", "Checking Directory[%s] DaysOld[%d]", fullFileName, daysOld)
+		l.Debug("LogDirectoryCleanup : Checking Directory[%s] DaysOld[%d]", fullFileName, daysOld)
 
 		if daysOld >= 0 {
-			l.Debug("LogDirectoryCleanup", "Removing Directory[%s]", fullFileName)
+			l.Debug("LogDirectoryCleanup : Removing Directory[%s]", fullFileName)
+
+			if err := l.notifyCleanup(fullFileName, cleanupReasonAge); err != nil {
+				l.ErrorWith(err, "LogDirectoryCleanup : OnCleanup Skipped Directory [%s] :", fullFileName)
+				continue
+			}
 
 			err = os.RemoveAll(fullFileName)
 			if err != nil {
-				l.Debug("LogDirectoryCleanup", "Attempting To Remove Directory [%s]", fullFileName)
+				l.Debug("LogDirectoryCleanup : Attempting To Remove Directory [%s]", fullFileName)
 				continue
 			}
 
-			l.Debug("LogDirectoryCleanup", "Directory Removed [%s]", fullFileName)
+			l.Debug("LogDirectoryCleanup : Directory Removed [%s]", fullFileName)
+			continue
+		}
+
+		if l.CompressOldFiles {
+			l.compressDirectory(fullFileName)
 		}
 	}
 
@@ -251,140 +1214,431 @@ func (l *Logger) LogDirectoryCleanup(baseFilePath string, daysToKeep int) {
 	return
 }
 
-//** STARTED AND COMPLETED
+// DryRunCleanup returns the date directories and log files LogDirectoryCleanup
+// would remove, without removing anything or calling OnCleanup. Directories
+// with names that are not dates are left out, as LogDirectoryCleanup skips them.
+func (l *Logger) DryRunCleanup(baseFilePath string, daysToKeep int) ([]string, error) {
+	// There is no local directory behind a writer set with SetFileWriter.
+	if l.instance().fileWriter != nil {
+		return nil, nil
+	}
 
-// Started uses the Serialize destination and adds a Started tag to the log line
-func (l *Logger) Started(functionName string) {
-	logger.Debug.Output(2, fmt.Sprintf("%s Started\n", formatFuncName(functionName)))
-}
+	fileInfos, err := ioutil.ReadDir(baseFilePath)
+	if err != nil {
+		return nil, err
+	}
 
-// Startedf uses the Serialize destination and writes a Started tag to the log line
-func (l *Logger) Startedf(functionName string, format string, a ...interface{}) {
-	logger.Debug.Output(2, fmt.Sprintf("%s Started %s\n", formatFuncName(functionName), fmt.Sprintf(format, a...)))
-}
+	currentDate := l.now()
+	compareDate := time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day()-daysToKeep, 0, 0, 0, 0, currentDate.Location())
 
-// Completed uses the Serialize destination and writes a Completed tag to the log line
-func (l *Logger) Completed(functionName string) {
-	logger.Debug.Output(2, fmt.Sprintf("%s  Completed\n", formatFuncName(functionName)))
-}
+	var paths []string
+	for _, fileInfo := range fileInfos {
+		var date time.Time
+		if fileInfo.IsDir() {
+			// The directory name look like: YYYY-MM-DD
+			date, err = time.ParseInLocation("2006-01-02", fileInfo.Name(), compareDate.Location())
+			if err != nil {
+				continue
+			}
+		} else {
+			if !l.isCleanupFile(fileInfo.Name()) {
+				continue
+			}
+			modTime := fileInfo.ModTime().In(compareDate.Location())
+			date = time.Date(modTime.Year(), modTime.Month(), modTime.Day(), 0, 0, 0, 0, compareDate.Location())
+		}
 
-// Completedf uses the Serialize destination and writes a Completed tag to the log line
-func (l *Logger) Completedf(functionName string, format string, a ...interface{}) {
-	logger.Debug.Output(2, fmt.Sprintf("%s Completed %s\n", formatFuncName(functionName), fmt.Sprintf(format, a...)))
+		if int(compareDate.Sub(date).Hours()/24) >= 0 {
+			paths = append(paths, fmt.Sprintf("%s/%s", baseFilePath, fileInfo.Name()))
+		}
+	}
+	return paths, nil
 }
 
-// CompletedError uses the Error destination and writes a Completed tag to the log line
-func (l *Logger) CompletedError(functionName string, err error) {
-	logger.Error.Output(2, fmt.Sprintf("%s Completed with ERROR : %s\n", formatFuncName(functionName), err))
-}
+// LogDirectoryCleanupByCount keeps the maxDirs newest date directories and
+// removes the older ones, for when disk space matters more than age.
+// A maxDirs of 0 or less removes nothing.
+func (l *Logger) LogDirectoryCleanupByCount(baseFilePath string, maxDirs int) {
+	l.Startedf("LogDirectoryCleanupByCount", "BaseFilePath[%s] MaxDirs[%d]", baseFilePath, maxDirs)
 
-// CompletedErrorf uses the Error destination and writes a Completed tag to the log line
-func (l *Logger) CompletedErrorf(functionName string, err error, format string, a ...interface{}) {
-	logger.Error.Output(2, fmt.Sprintf("%s Completed with ERROR : %s : %s\n", formatFuncName(functionName), fmt.Sprintf(format, a...), err))
-}
+	if maxDirs <= 0 {
+		l.Completedf("LogDirectoryCleanupByCount", "Skipped, MaxDirs is not positive")
+		return
+	}
 
-//** DEBUG
+	// There is no local directory behind a writer set with SetFileWriter.
+	if l.instance().fileWriter != nil {
+		l.Completedf("LogDirectoryCleanupByCount", "Skipped, a file writer is in use")
+		return
+	}
 
-// Debug writes to the Debug destination
-func (l *Logger) Debug(format string, a ...interface{}) {
-	logger.Debug.Output(2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
-}
+	fileInfos, err := ioutil.ReadDir(baseFilePath)
+	if err != nil {
+		l.CompletedError("LogDirectoryCleanupByCount", err)
+		return
+	}
 
-//** INFO
+	// The directory names look like YYYY-MM-DD, ReadDir sorts them oldest first.
+	var dirs []string
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", fileInfo.Name()); err != nil {
+			continue
+		}
+		dirs = append(dirs, fileInfo.Name())
+	}
 
-// Info writes to the Info destination
-func (l *Logger) Info(format string, a ...interface{}) {
-	logger.Info.Output(2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
-}
+	for len(dirs) > maxDirs {
+		fullFileName := fmt.Sprintf("%s/%s", baseFilePath, dirs[0])
+		dirs = dirs[1:]
 
-// Info godoc
-func Info(format string, a ...interface{}) {
-	logger.Info.Output(2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
-}
+		l.Debug("LogDirectoryCleanupByCount : Removing Directory[%s]", fullFileName)
+
+		if err := l.notifyCleanup(fullFileName, cleanupReasonCount); err != nil {
+			l.ErrorWith(err, "LogDirectoryCleanupByCount : OnCleanup Skipped Directory [%s] :", fullFileName)
+			continue
+		}
 
-//** WARNING
+		if err := os.RemoveAll(fullFileName); err != nil {
+			l.ErrorWith(err, "LogDirectoryCleanupByCount : Failed to Remove Directory [%s] :", fullFileName)
+			continue
+		}
+
+		l.Debug("LogDirectoryCleanupByCount : Directory Removed [%s]", fullFileName)
+	}
 
-// Warning writes to the Warning destination
-func (l *Logger) Warning(format string, a ...interface{}) {
-	logger.Warning.Output(2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+	l.Completed("LogDirectoryCleanupByCount")
 }
 
-//** ERROR
+// logFileCleanup removes a log file kept outside of the date directories,
+// e.g. a compressed rotation, once it is as old as the directories being removed.
+func (l *Logger) logFileCleanup(baseFilePath string, fileInfo os.FileInfo, compareDate time.Time) {
+	if !l.isCleanupFile(fileInfo.Name()) {
+		return
+	}
+
+	// The file to check.
+	fullFileName := fmt.Sprintf("%s/%s", baseFilePath, fileInfo.Name())
+
+	// Files have no date in their name, use the day they were last written.
+	modTime := fileInfo.ModTime().In(compareDate.Location())
+	fileDate := time.Date(modTime.Year(), modTime.Month(), modTime.Day(), 0, 0, 0, 0, compareDate.Location())
+
+	// Compare the dates and convert to days.
+	daysOld := int(compareDate.Sub(fileDate).Hours() / 24)
+
+	l.Debug("LogDirectoryCleanup : Checking File[%s] DaysOld[%d]", fullFileName, daysOld)
+
+	if daysOld >= 0 {
+		l.Debug("LogDirectoryCleanup : Removing File[%s]", fullFileName)
+
+		if err := l.notifyCleanup(fullFileName, cleanupReasonAge); err != nil {
+			l.ErrorWith(err, "LogDirectoryCleanup : OnCleanup Skipped File [%s] :", fullFileName)
+			return
+		}
 
-// Error writes to the Error destination and accepts an err
-func (l *Logger) Error(err string) {
-	logger.Error.Output(2, fmt.Sprintf("%s\n", err))
+		if err := os.Remove(fullFileName); err != nil {
+			l.Debug("LogDirectoryCleanup : Attempting To Remove File [%s]", fullFileName)
+			return
+		}
+
+		l.Debug("LogDirectoryCleanup : File Removed [%s]", fullFileName)
+	}
 }
 
-// Errorf writes to the Error destination and accepts an err
-func (l *Logger) Errorf(format string, err error, a ...interface{}) {
-	logger.Error.Output(2, fmt.Sprintf("%s %s\n", fmt.Sprintf(format, a...), err))
+// notifyCleanup calls OnCleanup before path is removed, an error skips the removal
+func (l *Logger) notifyCleanup(path, reason string) error {
+	if l.OnCleanup == nil {
+		return nil
+	}
+	return l.OnCleanup(path, reason)
 }
 
-// ErrorG will be used for
-func (l *Logger) ErrorG(format string, a ...interface{}) {
-	logger.Error.Output(2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+// isCleanupFile reports whether the file name ends in one of the CleanupFileExtensions
+func (l *Logger) isCleanupFile(name string) bool {
+	extensions := l.CleanupFileExtensions
+	if extensions == nil {
+		extensions = defaultCleanupFileExtensions
+	}
+
+	for _, ext := range extensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
 }
 
 //* GIN LOGGER
 
+// GinLoggerConfig selects the optional request details GinLoggerWithConfig writes
+type GinLoggerConfig struct {
+	// LogAPIVersion writes the api_version of a versioned Accept header,
+	// e.g. v2 for application/vnd.myapi.v2+json
+	LogAPIVersion bool
+	// TenantExtractor returns the tenant of the request, e.g. from the JWT
+	// claims, written as tenant unless it is empty
+	TenantExtractor func(*gin.Context) string
+	// LogHeaders writes the values of these request headers as header.<name>,
+	// or as a headers object in JSON formats. Authorization, Cookie and
+	// Set-Cookie are always redacted.
+	LogHeaders []string
+	// LogForwardedFor writes the raw X-Forwarded-For chain as x_forwarded_for
+	// next to the client IP, cut to 512 bytes
+	LogForwardedFor bool
+	// SkipPaths are not logged, e.g. /healthz or /ping
+	SkipPaths []string
+	// SlowRequestThreshold writes requests taking longer at Warning level
+	SlowRequestThreshold time.Duration
+	// CustomFields returns fields added to the line of the request
+	CustomFields func(*gin.Context) map[string]string
+	// Output receives the lines instead of the level loggers when set,
+	// every request is written to it whatever the log level
+	Output io.Writer
+	// RouteLoggers write the requests of their route instead of the logger,
+	// e.g. a sampled logger for /metrics. Routes are matched by pattern,
+	// like /users/:id.
+	RouteLoggers map[string]*Logger
+	// QueryCountExtractor returns the number of database queries made by the
+	// request, written as db_queries, e.g. from a counter kept in the context
+	QueryCountExtractor func(*gin.Context) int64
+	// SlowQueryThreshold writes requests making more queries at Warning level
+	SlowQueryThreshold int64
+	// LogTraceContext writes the trace and span ids of the request as trace and
+	// span, from the span of GinTracingMiddleware or else the W3C traceparent
+	// header, to correlate the requests with their distributed traces
+	LogTraceContext bool
+}
+
 // GinLogger handler function to custom gin logger
 func (l *Logger) GinLogger() gin.HandlerFunc {
+	return l.GinLoggerWithConfig(GinLoggerConfig{})
+}
+
+// GinLoggerWithConfig is GinLogger writing the request details selected in cfg.
+// The details are added to text, CEF and JSON lines, the Apache and W3C formats are fixed.
+// It writes the entries of GinLoggerWithEntry, nothing with the nolog_all build tag.
+func (l *Logger) GinLoggerWithConfig(cfg GinLoggerConfig) gin.HandlerFunc {
+	if nologAll {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	skip := make(map[string]bool, len(cfg.SkipPaths))
+	for _, path := range cfg.SkipPaths {
+		skip[path] = true
+	}
+
 	return func(c *gin.Context) {
 		t := time.Now()
 		// process request
 		c.Next()
-		latency := time.Since(t)
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		statusCode := c.Writer.Status()
-		statusColor := colorForStatus(statusCode)
-		methodColor := colorForMethod(method)
-		path := c.Request.URL.Path
-
-		switch {
-		case statusCode >= 400 && statusCode <= 499:
-			{
-				l.Warning("[GIN] |\x1b[%dm %3d \x1b[%dm| %12v | %s |\x1b[%dm %-7s \x1b[%dm| %s %s",
-					statusColor, statusCode, colorReset,
-					latency,
-					clientIP,
-					methodColor, method, colorReset,
-					path,
-					c.Errors.String(),
-				)
+
+		if skip[c.Request.URL.Path] {
+			return
+		}
+
+		rl := l
+		if len(cfg.RouteLoggers) > 0 {
+			if routeLogger := cfg.RouteLoggers[routePattern(c)]; routeLogger != nil {
+				rl = routeLogger
 			}
-		case statusCode >= 500:
-			{
-				l.ErrorG("[GIN] |\x1b[%dm %3d \x1b[%dm| %12v | %s |\x1b[%dm %-7s \x1b[%dm| %s %s",
-					statusColor, statusCode, colorReset,
-					latency,
-					clientIP,
-					methodColor, method, colorReset,
-					path,
-					c.Errors.String(),
-				)
+		}
+
+		// The config reads the request while gin still holds it
+		queries, fields := cfg.requestFields(c, rl.Format.isJSON())
+		rl.writeGinEntry(cfg, newGinLogEntry(c, t), queries, fields)
+	}
+}
+
+// requestFields returns the number of queries and the fields of the request
+// selected in cfg, the queries are -1 without a QueryCountExtractor
+func (cfg GinLoggerConfig) requestFields(c *gin.Context, asObject bool) (int64, []Field) {
+	queries := int64(-1)
+	var fields []Field
+	if cfg.QueryCountExtractor != nil {
+		queries = cfg.QueryCountExtractor(c)
+		fields = append(fields, Field{Key: "db_queries", Value: queries})
+	}
+
+	if cfg.LogAPIVersion {
+		if version := apiVersion(c.Request.Header.Get("Accept")); version != "" {
+			fields = append(fields, Field{Key: "api_version", Value: version})
+		}
+	}
+
+	if cfg.TenantExtractor != nil {
+		if tenant := cfg.TenantExtractor(c); tenant != "" {
+			fields = append(fields, Field{Key: "tenant", Value: tenant})
+		}
+	}
+
+	if cfg.LogForwardedFor {
+		if forwardedFor := c.Request.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+			if len(forwardedFor) > maxForwardedFor {
+				forwardedFor = forwardedFor[:maxForwardedFor]
 			}
-		default:
-			l.Info("[GIN] |\x1b[%dm %3d \x1b[%dm| %12v | %s |\x1b[%dm %-7s \x1b[%dm| %s %s",
-				statusColor, statusCode, colorReset,
-				latency,
-				clientIP,
-				methodColor, method, colorReset,
-				path,
-				c.Errors.String(),
-			)
+			fields = append(fields, Field{Key: "x_forwarded_for", Value: forwardedFor})
+		}
+	}
+
+	if cfg.LogTraceContext {
+		span, ok := SpanFromContext(c)
+		if !ok {
+			span, ok = ParseTraceParent(c.Request.Header.Get("traceparent"))
+		}
+		if ok {
+			fields = append(fields, Field{Key: "trace", Value: span.TraceID}, Field{Key: "span", Value: span.SpanID})
+		}
+	}
+
+	if len(cfg.LogHeaders) > 0 {
+		fields = append(fields, headerFields(c.Request.Header, cfg.LogHeaders, asObject)...)
+	}
+
+	if cfg.CustomFields != nil {
+		fields = append(fields, customFields(cfg.CustomFields(c))...)
+	}
+	return queries, fields
+}
+
+// writeGinEntry writes the line of a request handled by GinLoggerWithConfig
+// with the queries and fields of requestFields
+func (l *Logger) writeGinEntry(cfg GinLoggerConfig, e GinLogEntry, queries int64, fields []Field) {
+	latency := e.Latency
+	clientIP := e.ClientIP
+	method := e.Method
+	path := e.Path
+	statusCode := e.Status
+
+	// the status is 0 once a websocket upgrade hijacked the connection
+	if statusCode == 0 {
+		l.Debug("[GIN] | connection hijacked | %12v | %s | %s %s", latency, clientIP, method, path)
+		return
+	}
+
+	level := levelForStatus(statusCode)
+	if cfg.SlowRequestThreshold > 0 && latency > cfg.SlowRequestThreshold && level == LevelInfo {
+		level = LevelWarn
+	}
+	if cfg.SlowQueryThreshold > 0 && queries > cfg.SlowQueryThreshold && level == LevelInfo {
+		level = LevelWarn
+	}
+
+	switch l.Format {
+	case FormatApacheCombined:
+		l.ginLine(cfg.Output, level, apacheCombined(e, l.DataTimeUTC))
+		return
+	case FormatW3CExtended:
+		l.ginLine(cfg.Output, level, w3cExtended(e))
+		return
+	case FormatCEF, FormatLog4j2JSON, FormatJSON:
+		msg := fmt.Sprintf("[GIN] %s %s", method, path)
+		fields = append([]Field{
+			{Key: "src", Value: clientIP},
+			{Key: "requestMethod", Value: method},
+			{Key: "request", Value: path},
+			{Key: "status", Value: statusCode},
+			{Key: "latency", Value: latency},
+			{Key: "errors", Value: e.errors},
+		}, fields...)
+		if cfg.Output != nil {
+			l.ginEntry(cfg.Output, level, msg, fields)
+			return
 		}
+		l.write(level, 1, msg, fields...)
+		return
+	}
+
+	msg := fmt.Sprintf("[GIN] |%s| %12v | %s |%s| %s %s%s",
+		colorize(fmt.Sprintf(" %3d ", statusCode), colorForStatus(statusCode), l.DisableColor),
+		latency,
+		clientIP,
+		colorize(fmt.Sprintf(" %-7s ", method), colorForMethod(method), l.DisableColor),
+		path,
+		e.errors,
+		textFields(fields),
+	)
+
+	if cfg.Output != nil {
+		l.ginEntry(cfg.Output, level, msg, nil)
+		return
+	}
+
+	switch level {
+	case LevelWarn:
+		l.Warning("%s", msg)
+	case LevelError:
+		l.ErrorG("%s", msg)
+	default:
+		l.Info("%s", msg)
+	}
+}
+
+// ginLine writes an Apache or W3C line to out, or to the level logger when out is nil
+func (l *Logger) ginLine(out io.Writer, level int32, line string) {
+	if out == nil {
+		l.writeLine(level, line)
+		return
+	}
 
+	if _, err := io.WriteString(out, line+"\n"); err != nil {
+		l.instance().writeFailed(level, err)
 	}
 }
 
+// ginEntry writes the request line to out in the configured format
+func (l *Logger) ginEntry(out io.Writer, level int32, msg string, fields []Field) {
+	entry := &LogEntry{
+		Level:     level,
+		Timestamp: time.Now(),
+		Message:   msg,
+		Fields:    fields,
+	}
+	if l.DataTimeUTC {
+		entry.Timestamp = entry.Timestamp.UTC()
+	}
+
+	line := entry.Format(l.Format)
+	if l.Format == FormatCEF {
+		line = cefEvent(entry, l.CEF)
+	}
+
+	if _, err := io.WriteString(out, line+"\n"); err != nil {
+		l.instance().writeFailed(level, err)
+	}
+}
+
+// customFields turns the CustomFields of a request into fields sorted by key
+func customFields(values map[string]string) []Field {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, Field{Key: k, Value: values[k]})
+	}
+	return fields
+}
+
 // colorize the log out put based on the need
 func colorize(s interface{}, c int, disableColor bool) string {
 	if disableColor {
-		return fmt.Sprintf("\x1b[%dm%v\x1b[0m", c, s)
+		return fmt.Sprint(s)
 	}
-	return fmt.Sprintf("%s", s)
+	return fmt.Sprintf("\x1b[%dm%v\x1b[0m", c, s)
+}
+
+// prefix is the level prefix of the text lines, JSON lines are never colored
+func (l *Logger) prefix(s string, c int) string {
+	if l.Format.isJSON() {
+		return s
+	}
+	return colorize(s, c, l.DisableColor)
 }
 
 // options to use UTC timestamps
@@ -410,7 +1664,69 @@ func formatFuncName(s string) string {
 	return fmt.Sprintf("%s()", s)
 }
 
-// color httpstatus it will always color it
+// maxForwardedFor is the longest X-Forwarded-For chain GinLogger writes
+const maxForwardedFor = 512
+
+// sensitiveHeaders are redacted whether or not they are in LogHeaders
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// headerFields returns the values of the named headers that are set, as
+// header.<name> fields or as a single headers field holding a map
+func headerFields(header http.Header, names []string, asObject bool) []Field {
+	var fields []Field
+	values := make(map[string]string, len(names))
+
+	for _, name := range names {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			value = redacted
+		}
+
+		if asObject {
+			values[name] = value
+			continue
+		}
+		fields = append(fields, Field{Key: "header." + name, Value: value})
+	}
+
+	if asObject && len(values) > 0 {
+		return []Field{{Key: "headers", Value: values}}
+	}
+	return fields
+}
+
+// apiVersionRegexp matches the version of a vendor media type, e.g. application/vnd.myapi.v2+json
+var apiVersionRegexp = regexp.MustCompile(`vnd\.[^\s,;]*?\.(v[0-9]+)\b`)
+
+// apiVersion returns the version of a versioned Accept header, or "" when there is none
+func apiVersion(accept string) string {
+	m := apiVersionRegexp.FindStringSubmatch(accept)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// level GinLogger writes a http status at
+func levelForStatus(code int) int32 {
+	switch {
+	case code >= 400 && code <= 499:
+		return LevelWarn
+	case code >= 500:
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// color httpstatus
 func colorForStatus(code int) int {
 	switch {
 	case code >= 200 && code <= 299:
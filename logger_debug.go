@@ -0,0 +1,160 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// nologDebug is true when the nolog_debug or nolog_all build tag compiles
+// the Trace and Debug levels out
+const nologDebug = false
+
+//** STARTED AND COMPLETED
+
+// Started uses the Serialize destination and adds a Started tag to the log line
+func (l *Logger) Started(functionName string) {
+	l.write(LevelDebug, 2, fmt.Sprintf("%s Started\n", formatFuncName(functionName)))
+}
+
+// Startedf uses the Serialize destination and writes a Started tag to the log line
+func (l *Logger) Startedf(functionName string, format string, a ...interface{}) {
+	l.write(LevelDebug, 2, fmt.Sprintf("%s Started %s\n", formatFuncName(functionName), fmt.Sprintf(format, a...)))
+}
+
+// Completed uses the Serialize destination and writes a Completed tag to the log line
+func (l *Logger) Completed(functionName string) {
+	l.write(LevelDebug, 2, fmt.Sprintf("%s  Completed\n", formatFuncName(functionName)))
+}
+
+// Completedf uses the Serialize destination and writes a Completed tag to the log line
+func (l *Logger) Completedf(functionName string, format string, a ...interface{}) {
+	l.write(LevelDebug, 2, fmt.Sprintf("%s Completed %s\n", formatFuncName(functionName), fmt.Sprintf(format, a...)))
+}
+
+//** TRACE
+
+// Trace writes to the Trace destination
+func (l *Logger) Trace(format string, a ...interface{}) {
+	l.write(LevelTrace, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// Tracef writes to the Trace destination and adds the function name to the log line
+func (l *Logger) Tracef(functionName string, format string, a ...interface{}) {
+	l.write(LevelTrace, 2, fmt.Sprintf("%s %s\n", formatFuncName(functionName), fmt.Sprintf(format, a...)))
+}
+
+// TraceFields writes msg and the fields to the Trace destination
+func (l *Logger) TraceFields(msg string, fields ...Fields) {
+	l.write(LevelTrace, 2, msg+"\n", ToFields(fields...)...)
+}
+
+// DumpRequest writes the request as it came over the wire to the Trace destination,
+// the body is cut at MaxBodyLogSize. Nothing is read unless Trace is enabled.
+func (l *Logger) DumpRequest(r *http.Request) {
+	if !l.IsLevelEnabled(LevelTrace) {
+		return
+	}
+
+	dump, err := l.dumpRequest(r)
+	if err != nil {
+		l.ErrorWith(err, "DumpRequest : Attempting To Dump Request [%s] :", r.URL)
+		return
+	}
+	l.write(LevelTrace, 2, fmt.Sprintf("DumpRequest :\n%s\n", dump))
+}
+
+// DumpResponse writes the response as it came over the wire to the Trace destination,
+// the body is cut at MaxBodyLogSize. Nothing is read unless Trace is enabled.
+func (l *Logger) DumpResponse(resp *http.Response) {
+	if !l.IsLevelEnabled(LevelTrace) {
+		return
+	}
+
+	dump, err := l.dumpResponse(resp)
+	if err != nil {
+		l.ErrorWith(err, "DumpResponse : Attempting To Dump Response [%s] :", resp.Status)
+		return
+	}
+	l.write(LevelTrace, 2, fmt.Sprintf("DumpResponse :\n%s\n", dump))
+}
+
+//** DEBUG
+
+// Debug writes to the Debug destination
+func (l *Logger) Debug(format string, a ...interface{}) {
+	if !l.sampledCall() {
+		return
+	}
+	l.write(LevelDebug, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// Debug writes to the Debug destination of the default ApplicationLog
+func Debug(format string, a ...interface{}) {
+	Default().config.write(LevelDebug, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// DebugCtx writes to the Debug destination with the ids found in ctx
+func (l *Logger) DebugCtx(ctx context.Context, format string, a ...interface{}) {
+	l.write(LevelDebug, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, a...)))
+}
+
+// DebugFields writes msg and the fields to the Debug destination
+func (l *Logger) DebugFields(msg string, fields ...Fields) {
+	l.write(LevelDebug, 2, msg+"\n", ToFields(fields...)...)
+}
+
+// Verbose writes to the Debug destination, JSON formats write VERBOSE as the level
+func (l *Logger) Verbose(format string, a ...interface{}) {
+	l.writeAs(LevelDebug, "VERBOSE", 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+//** APPLICATIONLOG
+
+// Trace writes to the Trace destination
+func (a *ApplicationLog) Trace(format string, args ...interface{}) {
+	a.config.write(LevelTrace, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
+
+// Tracef writes to the Trace destination and adds the function name to the log line
+func (a *ApplicationLog) Tracef(functionName string, format string, args ...interface{}) {
+	a.config.write(LevelTrace, 2, fmt.Sprintf("%s %s\n", formatFuncName(functionName), fmt.Sprintf(format, args...)))
+}
+
+// Started uses the Serialize destination and adds a Started tag to the log line
+func (a *ApplicationLog) Started(functionName string) {
+	a.config.write(LevelDebug, 2, fmt.Sprintf("%s Started\n", formatFuncName(functionName)))
+}
+
+// Startedf uses the Serialize destination and writes a Started tag to the log line
+func (a *ApplicationLog) Startedf(functionName string, format string, args ...interface{}) {
+	a.config.write(LevelDebug, 2, fmt.Sprintf("%s Started %s\n", formatFuncName(functionName), fmt.Sprintf(format, args...)))
+}
+
+// Completed uses the Serialize destination and writes a Completed tag to the log line
+func (a *ApplicationLog) Completed(functionName string) {
+	a.config.write(LevelDebug, 2, fmt.Sprintf("%s  Completed\n", formatFuncName(functionName)))
+}
+
+// Completedf uses the Serialize destination and writes a Completed tag to the log line
+func (a *ApplicationLog) Completedf(functionName string, format string, args ...interface{}) {
+	a.config.write(LevelDebug, 2, fmt.Sprintf("%s Completed %s\n", formatFuncName(functionName), fmt.Sprintf(format, args...)))
+}
+
+// Debug writes to the Debug destination
+func (a *ApplicationLog) Debug(format string, args ...interface{}) {
+	a.config.write(LevelDebug, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
+
+// DebugCtx writes to the Debug destination with the ids found in ctx
+func (a *ApplicationLog) DebugCtx(ctx context.Context, format string, args ...interface{}) {
+	a.config.write(LevelDebug, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, args...)))
+}
+
+// Verbose writes to the Debug destination, JSON formats write VERBOSE as the level
+func (a *ApplicationLog) Verbose(format string, args ...interface{}) {
+	a.config.writeAs(LevelDebug, "VERBOSE", 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,269 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"context"
+	"fmt"
+)
+
+// nologAll is true when the nolog_all build tag compiles every level out
+const nologAll = false
+
+//** COMPLETED WITH ERROR
+
+// CompletedError uses the Error destination and writes a Completed tag to the log line
+func (l *Logger) CompletedError(functionName string, err error) {
+	l.write(LevelError, 2, fmt.Sprintf("%s Completed with ERROR : %s\n", formatFuncName(functionName), err))
+}
+
+// CompletedErrorf uses the Error destination and writes a Completed tag to the log line
+func (l *Logger) CompletedErrorf(functionName string, err error, format string, a ...interface{}) {
+	l.write(LevelError, 2, fmt.Sprintf("%s Completed with ERROR : %s : %s\n", formatFuncName(functionName), fmt.Sprintf(format, a...), err))
+}
+
+//** INFO
+
+// Info writes to the Info destination
+func (l *Logger) Info(format string, a ...interface{}) {
+	if !l.sampledCall() {
+		return
+	}
+	l.write(LevelInfo, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// InfoCtx writes to the Info destination with the ids found in ctx
+func (l *Logger) InfoCtx(ctx context.Context, format string, a ...interface{}) {
+	l.write(LevelInfo, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, a...)))
+}
+
+// InfoFields writes msg and the fields to the Info destination, e.g.
+// l.InfoFields("Charge : Completed", StringField("order", id), IntField("items", n))
+func (l *Logger) InfoFields(msg string, fields ...Fields) {
+	l.write(LevelInfo, 2, msg+"\n", ToFields(fields...)...)
+}
+
+// Info godoc
+func Info(format string, a ...interface{}) {
+	Default().config.write(LevelInfo, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+//** WARNING
+
+// Warning writes to the Warning destination
+func (l *Logger) Warning(format string, a ...interface{}) {
+	if !l.sampledCall() {
+		return
+	}
+	l.write(LevelWarn, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// Warn is Warning, named after LevelWarn
+func (l *Logger) Warn(format string, a ...interface{}) {
+	if !l.sampledCall() {
+		return
+	}
+	l.write(LevelWarn, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// Warn writes to the Warning destination of the default ApplicationLog
+func Warn(format string, a ...interface{}) {
+	Default().config.write(LevelWarn, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// WarningCtx writes to the Warning destination with the ids found in ctx
+func (l *Logger) WarningCtx(ctx context.Context, format string, a ...interface{}) {
+	l.write(LevelWarn, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, a...)))
+}
+
+// WarningFields writes msg and the fields to the Warning destination
+func (l *Logger) WarningFields(msg string, fields ...Fields) {
+	l.write(LevelWarn, 2, msg+"\n", ToFields(fields...)...)
+}
+
+//** ERROR
+
+// Error writes to the Error destination and accepts an err
+func (l *Logger) Error(err string) {
+	if !l.sampledCall() {
+		return
+	}
+	l.write(LevelError, 2, fmt.Sprintf("%s\n", err))
+}
+
+// Error writes to the Error destination of the default ApplicationLog
+func Error(err string) {
+	Default().config.write(LevelError, 2, fmt.Sprintf("%s\n", err))
+}
+
+// Errorf writes to the Error destination and accepts an err
+//
+// Deprecated: the err between format and its arguments trips go vet, use ErrorWith.
+func (l *Logger) Errorf(format string, err error, a ...interface{}) {
+	l.write(LevelError, 2, fmt.Sprintf("%s %s\n", fmt.Sprintf(format, a...), err))
+}
+
+// ErrorWith writes the formatted message followed by err to the Error destination
+func (l *Logger) ErrorWith(err error, format string, a ...interface{}) {
+	l.write(LevelError, 2, fmt.Sprintf("%s %s\n", fmt.Sprintf(format, a...), err))
+}
+
+// ErrorCtx writes to the Error destination with the ids found in ctx
+func (l *Logger) ErrorCtx(ctx context.Context, format string, a ...interface{}) {
+	l.write(LevelError, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, a...)))
+}
+
+// ErrorFields writes msg and the fields to the Error destination
+func (l *Logger) ErrorFields(msg string, fields ...Fields) {
+	l.write(LevelError, 2, msg+"\n", ToFields(fields...)...)
+}
+
+// ErrorG will be used for
+func (l *Logger) ErrorG(format string, a ...interface{}) {
+	l.write(LevelError, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+// Critical writes to the Error destination, JSON formats write CRITICAL as the level
+func (l *Logger) Critical(format string, a ...interface{}) {
+	l.writeAs(LevelError, "CRITICAL", 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, a...)))
+}
+
+//** PANIC
+
+// Panic writes to the Error destination, JSON formats write PANIC as the level,
+// and panics with the message so a recovery like GinRecovery can catch it
+func (l *Logger) Panic(format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	l.panicOutput(msg).panicWith(msg)
+}
+
+// Panicf is Panic that adds the function name to the log line
+func (l *Logger) Panicf(functionName string, format string, a ...interface{}) {
+	msg := fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...))
+	l.panicOutput(msg).panicWith(msg)
+}
+
+// panicOutput writes the PANIC line of msg for the caller of the method
+// calling it and returns the ApplicationLog that panics
+func (l *Logger) panicOutput(msg string) *ApplicationLog {
+	app := l.instance()
+	l.outputAs(LevelError, "PANIC", l.callerDepth(3), msg+"\n")
+	return app
+}
+
+//** FATAL
+
+// Fatal writes to the Error destination, flushes the log files and exits with status 1
+func (l *Logger) Fatal(format string, a ...interface{}) {
+	l.fatalOutput(fmt.Sprintf(format, a...)).exit()
+}
+
+// Fatalf is Fatal that adds the function name to the log line
+func (l *Logger) Fatalf(functionName string, format string, a ...interface{}) {
+	l.fatalOutput(fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...))).exit()
+}
+
+// fatalOutput writes the Fatal line of msg for the caller of the method
+// calling it and returns the ApplicationLog that exits
+func (l *Logger) fatalOutput(msg string) *ApplicationLog {
+	app := l.instance()
+	l.outputAs(LevelFatal, "", l.callerDepth(3), msg+"\n")
+	return app
+}
+
+//** APPLICATIONLOG
+
+// CompletedError uses the Error destination and writes a Completed tag to the log line
+func (a *ApplicationLog) CompletedError(functionName string, err error) {
+	a.config.write(LevelError, 2, fmt.Sprintf("%s Completed with ERROR : %s\n", formatFuncName(functionName), err))
+}
+
+// CompletedErrorf uses the Error destination and writes a Completed tag to the log line
+func (a *ApplicationLog) CompletedErrorf(functionName string, err error, format string, args ...interface{}) {
+	a.config.write(LevelError, 2, fmt.Sprintf("%s Completed with ERROR : %s : %s\n", formatFuncName(functionName), fmt.Sprintf(format, args...), err))
+}
+
+// Info writes to the Info destination
+func (a *ApplicationLog) Info(format string, args ...interface{}) {
+	a.config.write(LevelInfo, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
+
+// InfoCtx writes to the Info destination with the ids found in ctx
+func (a *ApplicationLog) InfoCtx(ctx context.Context, format string, args ...interface{}) {
+	a.config.write(LevelInfo, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, args...)))
+}
+
+// Warning writes to the Warning destination
+func (a *ApplicationLog) Warning(format string, args ...interface{}) {
+	a.config.write(LevelWarn, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
+
+// Warn is Warning, named after LevelWarn
+func (a *ApplicationLog) Warn(format string, args ...interface{}) {
+	a.config.write(LevelWarn, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
+
+// WarningCtx writes to the Warning destination with the ids found in ctx
+func (a *ApplicationLog) WarningCtx(ctx context.Context, format string, args ...interface{}) {
+	a.config.write(LevelWarn, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, args...)))
+}
+
+// Error writes to the Error destination and accepts an err
+func (a *ApplicationLog) Error(err string) {
+	a.config.write(LevelError, 2, fmt.Sprintf("%s\n", err))
+}
+
+// Errorf writes to the Error destination and accepts an err
+//
+// Deprecated: the err between format and its arguments trips go vet, use ErrorWith.
+func (a *ApplicationLog) Errorf(format string, err error, args ...interface{}) {
+	a.config.write(LevelError, 2, fmt.Sprintf("%s %s\n", fmt.Sprintf(format, args...), err))
+}
+
+// ErrorWith writes the formatted message followed by err to the Error destination
+func (a *ApplicationLog) ErrorWith(err error, format string, args ...interface{}) {
+	a.config.write(LevelError, 2, fmt.Sprintf("%s %s\n", fmt.Sprintf(format, args...), err))
+}
+
+// ErrorCtx writes to the Error destination with the ids found in ctx
+func (a *ApplicationLog) ErrorCtx(ctx context.Context, format string, args ...interface{}) {
+	a.config.write(LevelError, 2, fmt.Sprintf("%s\n", contextMessage(ctx, format, args...)))
+}
+
+// ErrorG will be used for
+func (a *ApplicationLog) ErrorG(format string, args ...interface{}) {
+	a.config.write(LevelError, 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
+
+// Critical writes to the Error destination, JSON formats write CRITICAL as the level
+func (a *ApplicationLog) Critical(format string, args ...interface{}) {
+	a.config.writeAs(LevelError, "CRITICAL", 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+}
+
+// Fatal writes to the Error destination, flushes the log files and exits with status 1
+func (a *ApplicationLog) Fatal(format string, args ...interface{}) {
+	a.config.outputAs(LevelFatal, "", 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+	a.exit()
+}
+
+// Fatalf is Fatal that adds the function name to the log line
+func (a *ApplicationLog) Fatalf(functionName string, format string, args ...interface{}) {
+	a.config.outputAs(LevelFatal, "", 2, fmt.Sprintf("%s %s\n", formatFuncName(functionName), fmt.Sprintf(format, args...)))
+	a.exit()
+}
+
+// Panic writes to the Error destination, JSON formats write PANIC as the level,
+// and panics with the message so a recovery like GinRecovery can catch it
+func (a *ApplicationLog) Panic(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	a.config.outputAs(LevelError, "PANIC", 2, msg+"\n")
+	a.panicWith(msg)
+}
+
+// Panicf is Panic that adds the function name to the log line
+func (a *ApplicationLog) Panicf(functionName string, format string, args ...interface{}) {
+	msg := fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, args...))
+	a.config.outputAs(LevelError, "PANIC", 2, msg+"\n")
+	a.panicWith(msg)
+}
@@ -0,0 +1,94 @@
+//go:build nolog_debug || nolog_all
+// +build nolog_debug nolog_all
+
+// Building with -tags nolog_debug compiles the Trace and Debug levels out: the calls
+// below are empty so the arguments are never formatted. It is meant for latency
+// critical builds where even checking the level on every call costs too much.
+
+package applogger
+
+import (
+	"context"
+	"net/http"
+)
+
+// nologDebug is true when the nolog_debug or nolog_all build tag compiles
+// the Trace and Debug levels out
+const nologDebug = true
+
+//** STARTED AND COMPLETED
+
+// Started is compiled out by the nolog_debug build tag
+func (l *Logger) Started(functionName string) {}
+
+// Startedf is compiled out by the nolog_debug build tag
+func (l *Logger) Startedf(functionName string, format string, a ...interface{}) {}
+
+// Completed is compiled out by the nolog_debug build tag
+func (l *Logger) Completed(functionName string) {}
+
+// Completedf is compiled out by the nolog_debug build tag
+func (l *Logger) Completedf(functionName string, format string, a ...interface{}) {}
+
+//** TRACE
+
+// Trace is compiled out by the nolog_debug build tag
+func (l *Logger) Trace(format string, a ...interface{}) {}
+
+// Tracef is compiled out by the nolog_debug build tag
+func (l *Logger) Tracef(functionName string, format string, a ...interface{}) {}
+
+// TraceFields is compiled out by the nolog_debug build tag
+func (l *Logger) TraceFields(msg string, fields ...Fields) {}
+
+// DumpRequest is compiled out by the nolog_debug build tag
+func (l *Logger) DumpRequest(r *http.Request) {}
+
+// DumpResponse is compiled out by the nolog_debug build tag
+func (l *Logger) DumpResponse(resp *http.Response) {}
+
+//** DEBUG
+
+// Debug is compiled out by the nolog_debug build tag
+func (l *Logger) Debug(format string, a ...interface{}) {}
+
+// Debug is compiled out by the nolog_debug build tag
+func Debug(format string, a ...interface{}) {}
+
+// DebugCtx is compiled out by the nolog_debug build tag
+func (l *Logger) DebugCtx(ctx context.Context, format string, a ...interface{}) {}
+
+// DebugFields is compiled out by the nolog_debug build tag
+func (l *Logger) DebugFields(msg string, fields ...Fields) {}
+
+// Verbose is compiled out by the nolog_debug build tag
+func (l *Logger) Verbose(format string, a ...interface{}) {}
+
+//** APPLICATIONLOG
+
+// Trace is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Trace(format string, args ...interface{}) {}
+
+// Tracef is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Tracef(functionName string, format string, args ...interface{}) {}
+
+// Started is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Started(functionName string) {}
+
+// Startedf is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Startedf(functionName string, format string, args ...interface{}) {}
+
+// Completed is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Completed(functionName string) {}
+
+// Completedf is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Completedf(functionName string, format string, args ...interface{}) {}
+
+// Debug is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Debug(format string, args ...interface{}) {}
+
+// DebugCtx is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) DebugCtx(ctx context.Context, format string, args ...interface{}) {}
+
+// Verbose is compiled out by the nolog_debug build tag
+func (a *ApplicationLog) Verbose(format string, args ...interface{}) {}
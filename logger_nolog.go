@@ -0,0 +1,186 @@
+//go:build nolog_all
+// +build nolog_all
+
+// Building with -tags nolog_all compiles every level out, the binary writes
+// no log lines but the Fatal line, kept so the reason of the exit is not lost.
+// GinLogger writes no requests either, whatever the configured format.
+
+package applogger
+
+import (
+	"context"
+	"fmt"
+)
+
+// nologAll is true when the nolog_all build tag compiles every level out
+const nologAll = true
+
+//** COMPLETED WITH ERROR
+
+// CompletedError is compiled out by the nolog_all build tag
+func (l *Logger) CompletedError(functionName string, err error) {}
+
+// CompletedErrorf is compiled out by the nolog_all build tag
+func (l *Logger) CompletedErrorf(functionName string, err error, format string, a ...interface{}) {}
+
+//** INFO
+
+// Info is compiled out by the nolog_all build tag
+func (l *Logger) Info(format string, a ...interface{}) {}
+
+// InfoCtx is compiled out by the nolog_all build tag
+func (l *Logger) InfoCtx(ctx context.Context, format string, a ...interface{}) {}
+
+// InfoFields is compiled out by the nolog_all build tag
+func (l *Logger) InfoFields(msg string, fields ...Fields) {}
+
+// Info is compiled out by the nolog_all build tag
+func Info(format string, a ...interface{}) {}
+
+//** WARNING
+
+// Warning is compiled out by the nolog_all build tag
+func (l *Logger) Warning(format string, a ...interface{}) {}
+
+// Warn is compiled out by the nolog_all build tag
+func (l *Logger) Warn(format string, a ...interface{}) {}
+
+// Warn is compiled out by the nolog_all build tag
+func Warn(format string, a ...interface{}) {}
+
+// WarningCtx is compiled out by the nolog_all build tag
+func (l *Logger) WarningCtx(ctx context.Context, format string, a ...interface{}) {}
+
+// WarningFields is compiled out by the nolog_all build tag
+func (l *Logger) WarningFields(msg string, fields ...Fields) {}
+
+//** ERROR
+
+// Error is compiled out by the nolog_all build tag
+func (l *Logger) Error(err string) {}
+
+// Error is compiled out by the nolog_all build tag
+func Error(err string) {}
+
+// Errorf is compiled out by the nolog_all build tag
+//
+// Deprecated: the err between format and its arguments trips go vet, use ErrorWith.
+func (l *Logger) Errorf(format string, err error, a ...interface{}) {}
+
+// ErrorWith is compiled out by the nolog_all build tag
+func (l *Logger) ErrorWith(err error, format string, a ...interface{}) {}
+
+// ErrorCtx is compiled out by the nolog_all build tag
+func (l *Logger) ErrorCtx(ctx context.Context, format string, a ...interface{}) {}
+
+// ErrorFields is compiled out by the nolog_all build tag
+func (l *Logger) ErrorFields(msg string, fields ...Fields) {}
+
+// ErrorG is compiled out by the nolog_all build tag
+func (l *Logger) ErrorG(format string, a ...interface{}) {}
+
+// Critical is compiled out by the nolog_all build tag
+func (l *Logger) Critical(format string, a ...interface{}) {}
+
+//** PANIC
+
+// Panic writes nothing with the nolog_all build tag but still panics
+func (l *Logger) Panic(format string, a ...interface{}) {
+	l.instance().panicWith(fmt.Sprintf(format, a...))
+}
+
+// Panicf writes nothing with the nolog_all build tag but still panics
+func (l *Logger) Panicf(functionName string, format string, a ...interface{}) {
+	l.instance().panicWith(fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...)))
+}
+
+//** FATAL
+
+// Fatal still writes its line with the nolog_all build tag, flushes the log files and exits
+func (l *Logger) Fatal(format string, a ...interface{}) {
+	l.fatalOutput(fmt.Sprintf(format, a...)).exit()
+}
+
+// Fatalf is Fatal that adds the function name to the log line
+func (l *Logger) Fatalf(functionName string, format string, a ...interface{}) {
+	l.fatalOutput(fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...))).exit()
+}
+
+// panicOutput writes nothing with the nolog_all build tag
+func (l *Logger) panicOutput(msg string) *ApplicationLog {
+	return l.instance()
+}
+
+// fatalOutput writes the Fatal line of msg for the caller of the method
+// calling it and returns the ApplicationLog that exits
+func (l *Logger) fatalOutput(msg string) *ApplicationLog {
+	app := l.instance()
+	l.outputAs(LevelFatal, "", l.callerDepth(3), msg+"\n")
+	return app
+}
+
+//** APPLICATIONLOG
+
+// CompletedError is compiled out by the nolog_all build tag
+func (a *ApplicationLog) CompletedError(functionName string, err error) {}
+
+// CompletedErrorf is compiled out by the nolog_all build tag
+func (a *ApplicationLog) CompletedErrorf(functionName string, err error, format string, args ...interface{}) {
+}
+
+// Info is compiled out by the nolog_all build tag
+func (a *ApplicationLog) Info(format string, args ...interface{}) {}
+
+// InfoCtx is compiled out by the nolog_all build tag
+func (a *ApplicationLog) InfoCtx(ctx context.Context, format string, args ...interface{}) {}
+
+// Warning is compiled out by the nolog_all build tag
+func (a *ApplicationLog) Warning(format string, args ...interface{}) {}
+
+// Warn is compiled out by the nolog_all build tag
+func (a *ApplicationLog) Warn(format string, args ...interface{}) {}
+
+// WarningCtx is compiled out by the nolog_all build tag
+func (a *ApplicationLog) WarningCtx(ctx context.Context, format string, args ...interface{}) {}
+
+// Error is compiled out by the nolog_all build tag
+func (a *ApplicationLog) Error(err string) {}
+
+// Errorf is compiled out by the nolog_all build tag
+//
+// Deprecated: the err between format and its arguments trips go vet, use ErrorWith.
+func (a *ApplicationLog) Errorf(format string, err error, args ...interface{}) {}
+
+// ErrorWith is compiled out by the nolog_all build tag
+func (a *ApplicationLog) ErrorWith(err error, format string, args ...interface{}) {}
+
+// ErrorCtx is compiled out by the nolog_all build tag
+func (a *ApplicationLog) ErrorCtx(ctx context.Context, format string, args ...interface{}) {}
+
+// ErrorG is compiled out by the nolog_all build tag
+func (a *ApplicationLog) ErrorG(format string, args ...interface{}) {}
+
+// Critical is compiled out by the nolog_all build tag
+func (a *ApplicationLog) Critical(format string, args ...interface{}) {}
+
+// Fatal still writes its line with the nolog_all build tag, flushes the log files and exits
+func (a *ApplicationLog) Fatal(format string, args ...interface{}) {
+	a.config.outputAs(LevelFatal, "", 2, fmt.Sprintf("%s\n", fmt.Sprintf(format, args...)))
+	a.exit()
+}
+
+// Fatalf is Fatal that adds the function name to the log line
+func (a *ApplicationLog) Fatalf(functionName string, format string, args ...interface{}) {
+	a.config.outputAs(LevelFatal, "", 2, fmt.Sprintf("%s %s\n", formatFuncName(functionName), fmt.Sprintf(format, args...)))
+	a.exit()
+}
+
+// Panic writes nothing with the nolog_all build tag but still panics
+func (a *ApplicationLog) Panic(format string, args ...interface{}) {
+	a.panicWith(fmt.Sprintf(format, args...))
+}
+
+// Panicf writes nothing with the nolog_all build tag but still panics
+func (a *ApplicationLog) Panicf(functionName string, format string, args ...interface{}) {
+	a.panicWith(fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,58 @@
+//go:build nolog_all
+// +build nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNologAllKeepsFatal(t *testing.T) {
+	var buf bytes.Buffer
+
+	var codes []int
+	l := &Logger{DisableColor: true, FileLogLevel: LevelTrace}
+	l.ExitFunc = func(c int) { codes = append(codes, c) }
+	l.StartWriter(LevelError, &buf)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Error("error")
+	want := callerLine(t)
+	l.Fatal("cannot open %s", "db")
+
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Fatalf("exit codes %v, want [1]", codes)
+	}
+	if out := buf.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, " "+want+": cannot open db\n") {
+		t.Errorf("only the Fatal line should be written:\n%s", out)
+	}
+}
+
+func TestNologAllGinLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, format := range []Format{FormatText, FormatJSON, FormatCEF, FormatApacheCombined} {
+		var logBuf, outBuf bytes.Buffer
+		l := &Logger{DisableColor: true, Format: format}
+		l.StartWriter(LevelInfo, &logBuf)
+
+		r := gin.New()
+		r.Use(l.GinLogger())
+		r.GET("/a", func(c *gin.Context) { c.Status(http.StatusOK) })
+		r.GET("/b", l.GinLoggerWithConfig(GinLoggerConfig{Output: &outBuf}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/b", nil))
+
+		if logBuf.Len() != 0 || outBuf.Len() != 0 {
+			t.Errorf("format %s wrote requests:\n%s%s", format, logBuf.String(), outBuf.String())
+		}
+	}
+}
@@ -0,0 +1,528 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestInstancesIsolated(t *testing.T) {
+	var debugBuf, errorBuf bytes.Buffer
+	debugLogger := &Logger{DisableColor: true}
+	errorLogger := &Logger{DisableColor: true, Format: FormatJSON}
+	debugApp := debugLogger.StartWriter(LevelDebug, &debugBuf)
+	errorApp := errorLogger.StartWriter(LevelError, &errorBuf)
+
+	if debugApp == errorApp {
+		t.Fatal("both loggers share the ApplicationLog")
+	}
+
+	debugLogger.Debug("debug of the first")
+	errorLogger.Debug("debug of the second")
+	errorLogger.Error("error of the second")
+
+	if !strings.Contains(debugBuf.String(), "DEBUG: ") || strings.Contains(debugBuf.String(), "second") {
+		t.Errorf("first logger output:\n%s", debugBuf.String())
+	}
+	if strings.Contains(errorBuf.String(), "debug") || !strings.Contains(errorBuf.String(), `"message":"error of the second"`) {
+		t.Errorf("second logger output:\n%s", errorBuf.String())
+	}
+
+	if err := errorLogger.SetLevel(LevelDebug); err != nil {
+		t.Fatal(err)
+	}
+	if debugApp.LogLevel() != LevelDebug || errorApp.LogLevel() != LevelDebug {
+		t.Errorf("levels %d and %d after SetLevel", debugApp.LogLevel(), errorApp.LogLevel())
+	}
+	if err := debugLogger.SetLevel(LevelWarn); err != nil {
+		t.Fatal(err)
+	}
+	if errorApp.LogLevel() != LevelDebug {
+		t.Error("SetLevel of the first logger changed the second")
+	}
+}
+
+func TestSetBuildInfoBeforeStart(t *testing.T) {
+	SetBuildInfo("1.2.3", "abc123", "")
+	defer SetBuildInfo("", "", "")
+
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelInfo, &buf)
+	l.Info("Main : Started")
+
+	if !strings.Contains(buf.String(), "Main : Started app_version=1.2.3 build_commit=abc123") {
+		t.Errorf("the build info set before Start is missing:\n%s", buf.String())
+	}
+
+	// A logger setting its own build info keeps it
+	var own bytes.Buffer
+	ol := &Logger{DisableColor: true, AppVersion: "2.0.0"}
+	ol.StartWriter(LevelInfo, &own)
+	ol.Info("Main : Started")
+	if !strings.Contains(own.String(), "app_version=2.0.0") || strings.Contains(own.String(), "abc123") {
+		t.Errorf("the logger build info was replaced:\n%s", own.String())
+	}
+}
+
+// recordingHook collects the messages it is fired with
+type recordingHook struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (h *recordingHook) Levels() []int32 { return []int32{LevelInfo} }
+
+func (h *recordingHook) Fire(level int32, message string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, message)
+	return nil
+}
+
+func TestConfigureBeforeStart(t *testing.T) {
+	l := &Logger{DisableColor: true}
+
+	hook := &recordingHook{}
+	l.AddHook(hook)
+
+	var tee bytes.Buffer
+	l.Tee(&tee)
+
+	l.AddTransform(func(e *LogEntry) *LogEntry {
+		e.Message = strings.Replace(e.Message, "secret", "[REDACTED]", -1)
+		return e
+	})
+
+	var buf syncBuffer
+	if err := l.Heartbeat(time.Millisecond, "Main : Alive"); err != nil {
+		t.Fatal(err)
+	}
+	l.StartWriter(LevelInfo, &buf)
+	l.Info("Main : Started secret")
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "Main : Alive") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	l.Stop()
+
+	out := buf.String()
+	if !strings.Contains(out, "Main : Started [REDACTED]") {
+		t.Errorf("the transform added before Start did not run:\n%s", out)
+	}
+	if !strings.Contains(out, "Main : Alive") {
+		t.Errorf("the heartbeat added before Start is missing:\n%s", out)
+	}
+	if !strings.Contains(tee.String(), "Main : Started") {
+		t.Errorf("the tee added before Start got %q", tee.String())
+	}
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if len(hook.messages) == 0 || !strings.Contains(hook.messages[0], "Main : Started") {
+		t.Errorf("the hook added before Start got %q", hook.messages)
+	}
+}
+
+func TestStopClearsLogFile(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{DisableColor: true}
+	a, err := l.TryStartFile(LevelInfo, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.LogFile == nil {
+		t.Fatal("no log file")
+	}
+
+	if err := l.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.LogFile != nil || a.logOut != nil {
+		t.Error("Stop kept the closed log file")
+	}
+}
+
+func TestStopNotStarted(t *testing.T) {
+	dir := tempDir(t)
+
+	first := &Logger{DisableColor: true}
+	a, err := first.TryStartFile(LevelInfo, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Stop()
+
+	second := &Logger{}
+	if err := second.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.LogFile == nil || a.logOut == nil {
+		t.Error("Stop of a logger that was never started closed the file of another logger")
+	}
+	if out := readLogFile(t, dir); strings.Contains(out, "Stop") {
+		t.Errorf("Stop of a logger that was never started wrote to another logger:\n%s", out)
+	}
+}
+
+func TestTryStartFile(t *testing.T) {
+	// A directory cannot be created under a file
+	file := filepath.Join(tempDir(t), "file")
+	if err := ioutil.WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	l := &Logger{}
+	a, err := l.TryStartFile(LevelInfo, file, 1)
+	if err == nil || a != nil {
+		t.Fatalf("TryStartFile under a file returned %v, %v", a, err)
+	}
+	if !strings.Contains(err.Error(), "Failed to Create log directory") {
+		t.Errorf("unexpected error %s", err)
+	}
+	if l.instance() != Default() {
+		t.Error("the logger was started without its file")
+	}
+}
+
+func TestMustStartFile(t *testing.T) {
+	l := &Logger{FileLogLevel: LevelInfo}
+	a := l.MustStartFile(LevelError, tempDir(t), 1)
+	defer l.Stop()
+	if a.LogFile == nil {
+		t.Fatal("no log file")
+	}
+
+	file := filepath.Join(tempDir(t), "file")
+	if err := ioutil.WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if _, ok := recover().(error); !ok {
+			t.Error("MustStartFile did not panic with the error")
+		}
+	}()
+	(&Logger{}).MustStartFile(LevelInfo, file, 1)
+}
+
+func TestColorize(t *testing.T) {
+	if got := colorize("INFO: ", colorBlue, true); got != "INFO: " {
+		t.Errorf("colorize with DisableColor = %q", got)
+	}
+	if got, want := colorize(404, colorRed, false), "\x1b[31m404\x1b[0m"; got != want {
+		t.Errorf("colorize = %q, want %q", got, want)
+	}
+}
+
+func TestDisableColor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, disable := range []bool{true, false} {
+		var buf bytes.Buffer
+		l := &Logger{DisableColor: disable, FileLogLevel: LevelInfo}
+		l.StartWriter(LevelError, &buf)
+
+		r := gin.New()
+		r.Use(l.GinLogger())
+		r.GET("/", func(c *gin.Context) {})
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		l.Info("line")
+
+		if escaped := strings.Contains(buf.String(), "\x1b["); escaped == disable {
+			t.Errorf("DisableColor %v, ANSI escapes %v:\n%q", disable, escaped, buf.String())
+		}
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	var all, alerts bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelTrace, &all)
+	if err := l.SetOutput(LevelError, &alerts); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetOutput(64, &alerts); err != ErrUnknownLevel {
+		t.Errorf("SetOutput(64) = %v, want ErrUnknownLevel", err)
+	}
+
+	l.Trace("trace line")
+	l.Debug("debug line")
+	l.Info("info line")
+	l.Warning("warning line")
+	l.ErrorG("error line")
+
+	for _, want := range []string{"TRACE: ", "DEBUG: ", "INFO: ", "WARNING: "} {
+		if n := strings.Count(all.String(), want); n != 1 {
+			t.Errorf("%d %q lines left in the writer of Start, want 1:\n%s", n, want, all.String())
+		}
+	}
+	if strings.Contains(all.String(), "error line") {
+		t.Errorf("the Error line was not moved:\n%s", all.String())
+	}
+	if out := alerts.String(); !strings.HasPrefix(out, "ERROR: ") || !strings.HasSuffix(out, ": error line\n") || strings.Count(out, "\n") != 1 {
+		t.Errorf("the new writer got more than the Error line:\n%s", out)
+	}
+}
+
+func TestStartedCompleted(t *testing.T) {
+	var debugBuf, errorBuf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.Start(LevelDebug)
+	defer l.Stop()
+	if err := l.SetOutput(LevelDebug, &debugBuf); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetOutput(LevelError, &errorBuf); err != nil {
+		t.Fatal(err)
+	}
+
+	err := errors.New("connection refused")
+	l.Started("LoadUser")
+	l.Startedf("LoadUser()", "ID[%d]", 7)
+	l.Completed("LoadUser")
+	l.Completedf("LoadUser", "Name[%s]", "ada")
+	l.CompletedError("SaveUser", err)
+	l.CompletedErrorf("SaveUser", err, "ID[%d]", 7)
+
+	debugLines := strings.Split(strings.TrimSpace(debugBuf.String()), "\n")
+	errorLines := strings.Split(strings.TrimSpace(errorBuf.String()), "\n")
+	if len(debugLines) != 4 || len(errorLines) != 2 {
+		t.Fatalf("%d debug and %d error lines, want 4 and 2:\n%s%s", len(debugLines), len(errorLines), debugBuf.String(), errorBuf.String())
+	}
+
+	for i, want := range []string{
+		"LoadUser() Started",
+		"LoadUser() Started ID[7]",
+		"LoadUser()  Completed",
+		"LoadUser() Completed Name[ada]",
+	} {
+		if !strings.HasPrefix(debugLines[i], "DEBUG: ") || !strings.HasSuffix(debugLines[i], ": "+want) {
+			t.Errorf("debug line %d = %q, want it to end with %q", i, debugLines[i], want)
+		}
+	}
+	for i, want := range []string{
+		"SaveUser() Completed with ERROR : connection refused",
+		"SaveUser() Completed with ERROR : ID[7] : connection refused",
+	} {
+		if !strings.HasPrefix(errorLines[i], "ERROR: ") || !strings.HasSuffix(errorLines[i], ": "+want) {
+			t.Errorf("error line %d = %q, want it to end with %q", i, errorLines[i], want)
+		}
+	}
+}
+
+func TestFormatFuncName(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"", "()"},
+		{"LoadUser", "LoadUser()"},
+		{"LoadUser()", "LoadUser()"},
+		{"users.LoadUser", "users.LoadUser()"},
+		{"users.(*Store).LoadUser()", "users.(*Store).LoadUser()"},
+	} {
+		if got := formatFuncName(tt.in); got != tt.want {
+			t.Errorf("formatFuncName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStartFileTwice(t *testing.T) {
+	dir := tempDir(t)
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo, FileNamePrefix: "first-"}
+	a, err := l.TryStartFile(LevelError, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := a.LogFile
+
+	// e.g. from init and then from main
+	l.FileNamePrefix = "second-"
+	if _, err := l.TryStartFile(LevelError, dir, 1); err != nil {
+		t.Fatal(err)
+	}
+	second := a.LogFile
+	defer l.Stop()
+
+	if second == first {
+		t.Fatal("the second StartFile kept the first file")
+	}
+	if _, err := first.Write([]byte("x")); err == nil {
+		t.Error("the first file is still open")
+	}
+
+	l.Info("to the second file")
+	if strings.Contains(readFile(t, first.Name()), "to the second file") || !strings.Contains(readFile(t, second.Name()), "to the second file") {
+		t.Error("the line was not written to the second file only")
+	}
+
+	l.Stop()
+	if _, err := second.Write([]byte("x")); err == nil {
+		t.Error("Stop left the second file open")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 4, 5, 0, time.UTC)
+	for _, tt := range []struct {
+		l    *Logger
+		want string
+	}{
+		{&Logger{}, "2024-01-15T10-04-05.txt"},
+		{&Logger{FileExtension: "log", FileNamePrefix: "web01-"}, "web01-2024-01-15T10-04-05.log"},
+		{&Logger{FileExtension: ".log"}, "2024-01-15T10-04-05.log"},
+		{&Logger{FileExtension: "log", FileNameFunc: func(t time.Time) string { return "app-" + t.Format("2006-01-02T15") + ".log" }}, "app-2024-01-15T10.log"},
+	} {
+		if got := tt.l.fileName(at); got != tt.want {
+			t.Errorf("fileName = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestStartFileName(t *testing.T) {
+	l := &Logger{FileLogLevel: LevelInfo, FileExtension: "log", FileNamePrefix: "web01-"}
+	a, err := l.TryStartFile(LevelError, tempDir(t), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	name := filepath.Base(a.LogFile.Name())
+	if !strings.HasPrefix(name, "web01-"+time.Now().Format("2006-01-02T")) || filepath.Ext(name) != ".log" {
+		t.Errorf("log file %s", name)
+	}
+}
+
+func TestStartFileTimeZone(t *testing.T) {
+	// 08:30 on the 16th in Sydney is still the 15th in UTC
+	sydney := time.FixedZone("AEDT", 11*60*60)
+	clock := timeNow
+	timeNow = func() time.Time { return time.Date(2024, 1, 16, 8, 30, 0, 0, sydney) }
+	defer func() { timeNow = clock }()
+
+	for _, tt := range []struct {
+		utc       bool
+		dir, file string
+	}{
+		{false, "2024-01-16", "2024-01-16T08-30-00.txt"},
+		{true, "2024-01-15", "2024-01-15T21-30-00.txt"},
+	} {
+		base := tempDir(t)
+		l := &Logger{FileLogLevel: LevelInfo, DataTimeUTC: tt.utc}
+		a, err := l.TryStartFile(LevelError, base, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := filepath.Join(base, tt.dir, tt.file); a.LogFile.Name() != want {
+			t.Errorf("DataTimeUTC %v: log file %s, want %s", tt.utc, a.LogFile.Name(), want)
+		}
+		l.Stop()
+	}
+}
+
+// wrapperError logs through two frames like a package wrapping the logger
+func wrapperError(l *Logger, msg string) {
+	wrapperLog(l, msg)
+}
+
+func wrapperLog(l *Logger, msg string) {
+	l.Error(msg)
+}
+
+func TestWithCallerDepth(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	wrapped := l.WithCallerDepth(defaultCallerDepth + 2)
+	if l.CallerDepth != 0 || wrapped.CallerDepth != 4 {
+		t.Fatalf("CallerDepth = %d and %d, the copy changed the logger", l.CallerDepth, wrapped.CallerDepth)
+	}
+
+	want := callerLine(t)
+	wrapperError(wrapped, "Wrapped : Failed")
+	if !strings.Contains(buf.String(), " "+want+": Wrapped : Failed") {
+		t.Errorf("caller %s missing in %q", want, buf.String())
+	}
+
+	buf.Reset()
+	wrapperError(l, "Direct : Failed")
+	if !strings.Contains(buf.String(), " logger_test.go:") || strings.Contains(buf.String(), want) {
+		t.Errorf("the default depth should write the wrapper line, got %q", buf.String())
+	}
+
+	buf.Reset()
+	want = callerLine(t)
+	l.Error("Direct : Failed")
+	if !strings.Contains(buf.String(), " "+want+": Direct : Failed") {
+		t.Errorf("caller %s missing in %q", want, buf.String())
+	}
+}
+
+func TestWithCallerDepthJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatJSON, DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	want := callerLine(t)
+	wrapperError(l.WithCallerDepth(4), "Wrapped : Failed")
+	if !strings.Contains(buf.String(), `"caller":"`+want+`"`) {
+		t.Errorf("caller %s missing in %s", want, buf.String())
+	}
+}
+
+func TestErrorWith(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	err := errors.New("connection refused")
+	l.ErrorWith(err, "Load : Failed [%s] after %d tries in %v, %.1f%% done", "a.txt", 3, 2*time.Second, 12.5)
+	l.ErrorWith(err, "Load : Failed")
+	l.Errorf("Load : Failed [%s] after %d tries", err, "a.txt", 3)
+
+	want := []string{
+		"Load : Failed [a.txt] after 3 tries in 2s, 12.5% done connection refused",
+		"Load : Failed connection refused",
+		"Load : Failed [a.txt] after 3 tries connection refused",
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, ": "+want[i]) {
+			t.Errorf("line %d = %q, want suffix %q", i, line, want[i])
+		}
+		if strings.Contains(line, "%!") {
+			t.Errorf("line %d has a bad verb: %q", i, line)
+		}
+	}
+}
+
+func TestMockLoggerErrorWith(t *testing.T) {
+	m := &MockLogger{}
+	m.ErrorWith(errors.New("timeout"), "Load : Failed [%s] after %d tries", "a.txt", 3)
+
+	if !m.WasCalledWith(LevelError, "Load : Failed [a.txt] after 3 tries timeout") {
+		t.Errorf("calls %v", m.Calls)
+	}
+}
@@ -0,0 +1,84 @@
+package applogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MemoryHandler is a Hook keeping the last lines written in a ring buffer,
+// so operators can read them through ServeHTTP without opening the log files.
+// It keeps the lines like MemoryHook, with their time.
+type MemoryHandler struct {
+	ring *memoryRing
+}
+
+// NewMemoryHandler creates a MemoryHandler keeping the last capacity lines,
+// attach it with AddHook
+func NewMemoryHandler(capacity int) *MemoryHandler {
+	return &MemoryHandler{ring: newMemoryRing(capacity)}
+}
+
+// Levels returns every level, the handler keeps all the lines
+func (h *MemoryHandler) Levels() []int32 {
+	return []int32{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError}
+}
+
+// Fire keeps the line, replacing the oldest once the buffer is full
+func (h *MemoryHandler) Fire(level int32, message string) error {
+	h.ring.add(LogEntry{Level: level, Timestamp: time.Now(), Message: message})
+	return nil
+}
+
+// Entries returns the kept lines, oldest first
+func (h *MemoryHandler) Entries() []LogEntry {
+	return h.ring.all()
+}
+
+// EntriesForLevel returns the kept lines of level, oldest first
+func (h *MemoryHandler) EntriesForLevel(level int32) []LogEntry {
+	var entries []LogEntry
+	for _, e := range h.Entries() {
+		if e.Level == level {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Reset drops the kept lines
+func (h *MemoryHandler) Reset() {
+	h.ring.reset()
+}
+
+// ServeHTTP responds with the kept lines as a JSON array, oldest first,
+// ?level=warn returns the lines of a single level
+func (h *MemoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	entries := h.Entries()
+	if name := r.URL.Query().Get("level"); name != "" {
+		level, err := parseLevelName(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entries = h.EntriesForLevel(level)
+	}
+
+	type memoryEntry struct {
+		Level     string    `json:"level"`
+		Timestamp time.Time `json:"timestamp"`
+		Message   string    `json:"message"`
+	}
+	out := make([]memoryEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, memoryEntry{
+			Level:     strings.ToLower(textLevel(&e)),
+			Timestamp: e.Timestamp,
+			Message:   e.Message,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
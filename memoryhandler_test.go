@@ -0,0 +1,106 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMemoryHandlerRing(t *testing.T) {
+	h := NewMemoryHandler(3)
+	for i := 0; i < 5; i++ {
+		h.Fire(LevelInfo, fmt.Sprintf("line %d", i))
+	}
+
+	entries := h.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("%d entries, want 3", len(entries))
+	}
+	for i, e := range entries {
+		if want := fmt.Sprintf("line %d", i+2); e.Message != want {
+			t.Errorf("entry %d = %q, want %q", i, e.Message, want)
+		}
+	}
+
+	h.Reset()
+	if entries := h.Entries(); len(entries) != 0 {
+		t.Errorf("%d entries after Reset", len(entries))
+	}
+}
+
+func TestMemoryHookRing(t *testing.T) {
+	h := NewMemoryHook(2, LevelWarn)
+	for i := 0; i < 3; i++ {
+		h.Fire(LevelWarn, fmt.Sprintf("line %d", i))
+	}
+
+	entries := h.Entries()
+	if len(entries) != 2 || entries[0] != (MemoryEntry{Level: LevelWarn, Message: "line 1"}) || entries[1].Message != "line 2" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
+
+func TestMemoryHandlerConcurrent(t *testing.T) {
+	h := NewMemoryHandler(100)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				h.Fire(LevelWarn, "line")
+				h.Entries()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if entries := h.EntriesForLevel(LevelWarn); len(entries) != 100 {
+		t.Errorf("%d entries, want 100", len(entries))
+	}
+}
+
+func TestMemoryHandlerServeHTTP(t *testing.T) {
+	h := NewMemoryHandler(10)
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.AddHook(h)
+	l.StartWriter(LevelError, &bytes.Buffer{})
+
+	l.Info("started")
+	l.Warning("slow disk")
+	l.Info("done")
+
+	var all []struct{ Level, Message string }
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[0].Level != "info" || !strings.HasSuffix(all[1].Message, ": slow disk") || all[1].Level != "warning" {
+		t.Errorf("unexpected entries %+v", all)
+	}
+
+	var warnings []struct{ Level, Message string }
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs?level=warn", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &warnings); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.HasSuffix(warnings[0].Message, ": slow disk") {
+		t.Errorf("unexpected warnings %+v", warnings)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs?level=loud", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown level: status %d", rec.Code)
+	}
+}
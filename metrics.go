@@ -0,0 +1,141 @@
+package applogger
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// openMetricsContentType is the content type of the OpenMetrics text format
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// defaultMetricBuckets are the upper bounds in seconds of the histogram
+// buckets, the defaults of the Prometheus client
+var defaultMetricBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metricSeries holds the values of one set of label values
+type metricSeries struct {
+	values []string
+	// counts are the observations per bucket, not cumulative
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// metricVec is a counter or a histogram of durations partitioned by labels,
+// written in the OpenMetrics text format
+type metricVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*metricSeries
+}
+
+// newHistogramVec returns a histogram of durations in seconds
+func newHistogramVec(name, help string, labels ...string) *metricVec {
+	return &metricVec{name: name, help: help, labels: labels, buckets: defaultMetricBuckets, series: make(map[string]*metricSeries)}
+}
+
+// newCounterVec returns a counter
+func newCounterVec(name, help string, labels ...string) *metricVec {
+	return &metricVec{name: name, help: help, labels: labels, series: make(map[string]*metricSeries)}
+}
+
+// observe adds d to the series of the label values, a counter counts it
+func (m *metricVec) observe(d time.Duration, values ...string) {
+	key := strings.Join(values, "\xff")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.series[key]
+	if s == nil {
+		s = &metricSeries{values: values, counts: make([]uint64, len(m.buckets))}
+		m.series[key] = s
+	}
+
+	seconds := d.Seconds()
+	s.count++
+	s.sum += seconds
+	for i, le := range m.buckets {
+		if seconds <= le {
+			s.counts[i]++
+			break
+		}
+	}
+}
+
+// writeTo writes the metric family in the OpenMetrics text format, the series
+// sorted by label values
+func (m *metricVec) writeTo(w io.Writer) error {
+	m.mu.Lock()
+	series := make([]metricSeries, 0, len(m.series))
+	for _, s := range m.series {
+		c := *s
+		c.counts = append([]uint64(nil), s.counts...)
+		series = append(series, c)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(series, func(i, j int) bool {
+		return strings.Join(series[i].values, "\xff") < strings.Join(series[j].values, "\xff")
+	})
+
+	kind := "counter"
+	if m.buckets != nil {
+		kind = "histogram"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# TYPE %s %s\n# HELP %s %s\n", m.name, kind, m.name, metricEscaper.Replace(m.help))
+	for _, s := range series {
+		labels := m.labelPairs(s.values)
+
+		if m.buckets == nil {
+			fmt.Fprintf(&b, "%s_total{%s} %d\n", m.name, labels, s.count)
+			continue
+		}
+
+		var cumulative uint64
+		for i, le := range m.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(&b, "%s_bucket{%s,le=\"%s\"} %d\n", m.name, labels, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %d\n", m.name, labels, s.count)
+		fmt.Fprintf(&b, "%s_count{%s} %d\n", m.name, labels, s.count)
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", m.name, labels, strconv.FormatFloat(s.sum, 'g', -1, 64))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labelPairs formats the labels with their values, e.g. route="/users/:id",method="GET"
+func (m *metricVec) labelPairs(values []string) string {
+	pairs := make([]string, len(m.labels))
+	for i, name := range m.labels {
+		pairs[i] = name + `="` + metricEscaper.Replace(values[i]) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+// metricEscaper escapes the label values and HELP texts of the OpenMetrics text format
+var metricEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeOpenMetrics writes the metric families followed by the # EOF marker
+func writeOpenMetrics(w io.Writer, metrics ...*metricVec) error {
+	for _, m := range metrics {
+		if err := m.writeTo(w); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "# EOF\n")
+	return err
+}
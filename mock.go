@@ -0,0 +1,215 @@
+package applogger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MockCall is a call recorded by MockLogger
+type MockCall struct {
+	Level  int32
+	Format string
+	Args   []interface{}
+}
+
+// Message returns the formatted message of the call
+func (c MockCall) Message() string {
+	if len(c.Args) == 0 {
+		return c.Format
+	}
+	return fmt.Sprintf(c.Format, c.Args...)
+}
+
+// MockLogger records the calls of the logging methods instead of writing them,
+// for unit tests that verify what was logged. The methods of the embedded Logger
+// that are not overridden, e.g. GinLogger, still write to the configured output.
+type MockLogger struct {
+	Logger
+
+	mu    sync.Mutex
+	Calls []MockCall
+}
+
+// record appends a call
+func (m *MockLogger) record(level int32, format string, a ...interface{}) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Level: level, Format: format, Args: a})
+	m.mu.Unlock()
+}
+
+// WasCalledWith reports whether a call at level has a message containing substr
+func (m *MockLogger) WasCalledWith(level int32, substr string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.Calls {
+		if c.Level == level && strings.Contains(c.Message(), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset removes the recorded calls
+func (m *MockLogger) Reset() {
+	m.mu.Lock()
+	m.Calls = nil
+	m.mu.Unlock()
+}
+
+// Started records a Debug call
+func (m *MockLogger) Started(functionName string) {
+	m.record(LevelDebug, "%s Started", formatFuncName(functionName))
+}
+
+// Startedf records a Debug call
+func (m *MockLogger) Startedf(functionName string, format string, a ...interface{}) {
+	m.record(LevelDebug, "%s Started %s", formatFuncName(functionName), fmt.Sprintf(format, a...))
+}
+
+// Completed records a Debug call
+func (m *MockLogger) Completed(functionName string) {
+	m.record(LevelDebug, "%s Completed", formatFuncName(functionName))
+}
+
+// Completedf records a Debug call
+func (m *MockLogger) Completedf(functionName string, format string, a ...interface{}) {
+	m.record(LevelDebug, "%s Completed %s", formatFuncName(functionName), fmt.Sprintf(format, a...))
+}
+
+// CompletedError records an Error call
+func (m *MockLogger) CompletedError(functionName string, err error) {
+	m.record(LevelError, "%s Completed with ERROR : %s", formatFuncName(functionName), err)
+}
+
+// CompletedErrorf records an Error call
+func (m *MockLogger) CompletedErrorf(functionName string, err error, format string, a ...interface{}) {
+	m.record(LevelError, "%s Completed with ERROR : %s : %s", formatFuncName(functionName), fmt.Sprintf(format, a...), err)
+}
+
+// Trace records a Trace call
+func (m *MockLogger) Trace(format string, a ...interface{}) {
+	m.record(LevelTrace, format, a...)
+}
+
+// Tracef records a Trace call
+func (m *MockLogger) Tracef(functionName string, format string, a ...interface{}) {
+	m.record(LevelTrace, "%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...))
+}
+
+// TraceFields records a Trace call, the fields follow the message as key=value
+func (m *MockLogger) TraceFields(msg string, fields ...Fields) {
+	m.record(LevelTrace, "%s", msg+textFields(ToFields(fields...)))
+}
+
+// Debug records a Debug call
+func (m *MockLogger) Debug(format string, a ...interface{}) {
+	m.record(LevelDebug, format, a...)
+}
+
+// DebugCtx records a Debug call with the ids found in ctx
+func (m *MockLogger) DebugCtx(ctx context.Context, format string, a ...interface{}) {
+	m.record(LevelDebug, "%s", contextMessage(ctx, format, a...))
+}
+
+// DebugFields records a Debug call, the fields follow the message as key=value
+func (m *MockLogger) DebugFields(msg string, fields ...Fields) {
+	m.record(LevelDebug, "%s", msg+textFields(ToFields(fields...)))
+}
+
+// Verbose records a Debug call
+func (m *MockLogger) Verbose(format string, a ...interface{}) {
+	m.record(LevelVerbose, format, a...)
+}
+
+// Info records an Info call
+func (m *MockLogger) Info(format string, a ...interface{}) {
+	m.record(LevelInfo, format, a...)
+}
+
+// InfoCtx records an Info call with the ids found in ctx
+func (m *MockLogger) InfoCtx(ctx context.Context, format string, a ...interface{}) {
+	m.record(LevelInfo, "%s", contextMessage(ctx, format, a...))
+}
+
+// InfoFields records an Info call, the fields follow the message as key=value
+func (m *MockLogger) InfoFields(msg string, fields ...Fields) {
+	m.record(LevelInfo, "%s", msg+textFields(ToFields(fields...)))
+}
+
+// Warning records a Warning call
+func (m *MockLogger) Warning(format string, a ...interface{}) {
+	m.record(LevelWarn, format, a...)
+}
+
+// Warn records a Warning call
+func (m *MockLogger) Warn(format string, a ...interface{}) {
+	m.record(LevelWarn, format, a...)
+}
+
+// WarningCtx records a Warning call with the ids found in ctx
+func (m *MockLogger) WarningCtx(ctx context.Context, format string, a ...interface{}) {
+	m.record(LevelWarn, "%s", contextMessage(ctx, format, a...))
+}
+
+// WarningFields records a Warning call, the fields follow the message as key=value
+func (m *MockLogger) WarningFields(msg string, fields ...Fields) {
+	m.record(LevelWarn, "%s", msg+textFields(ToFields(fields...)))
+}
+
+// Error records an Error call
+func (m *MockLogger) Error(err string) {
+	m.record(LevelError, err)
+}
+
+// Errorf records an Error call, the err is the last of the args
+func (m *MockLogger) Errorf(format string, err error, a ...interface{}) {
+	m.record(LevelError, format+" %s", append(append([]interface{}{}, a...), err)...)
+}
+
+// ErrorWith records an Error call, the err is the last of the args
+func (m *MockLogger) ErrorWith(err error, format string, a ...interface{}) {
+	m.record(LevelError, format+" %s", append(append([]interface{}{}, a...), err)...)
+}
+
+// ErrorCtx records an Error call with the ids found in ctx
+func (m *MockLogger) ErrorCtx(ctx context.Context, format string, a ...interface{}) {
+	m.record(LevelError, "%s", contextMessage(ctx, format, a...))
+}
+
+// ErrorFields records an Error call, the fields follow the message as key=value
+func (m *MockLogger) ErrorFields(msg string, fields ...Fields) {
+	m.record(LevelError, "%s", msg+textFields(ToFields(fields...)))
+}
+
+// ErrorG records an Error call
+func (m *MockLogger) ErrorG(format string, a ...interface{}) {
+	m.record(LevelError, format, a...)
+}
+
+// Critical records an Error call
+func (m *MockLogger) Critical(format string, a ...interface{}) {
+	m.record(LevelCritical, format, a...)
+}
+
+// Panic records an Error call, it does not panic
+func (m *MockLogger) Panic(format string, a ...interface{}) {
+	m.record(LevelError, format, a...)
+}
+
+// Panicf records an Error call, it does not panic
+func (m *MockLogger) Panicf(functionName string, format string, a ...interface{}) {
+	m.record(LevelError, "%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...))
+}
+
+// Fatal records a Fatal call, it does not exit
+func (m *MockLogger) Fatal(format string, a ...interface{}) {
+	m.record(LevelFatal, format, a...)
+}
+
+// Fatalf records a Fatal call, it does not exit
+func (m *MockLogger) Fatalf(functionName string, format string, a ...interface{}) {
+	m.record(LevelFatal, "%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...))
+}
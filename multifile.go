@@ -0,0 +1,97 @@
+package applogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LevelCleanup is the cleanup config for the log directory of one level
+type LevelCleanup struct {
+	BaseFilePath string
+	DaysToKeep   int
+}
+
+// StartMultiFile initializes ApplicationLog and only displays the specified logging level
+// like StartFile, but writes each level to its own file. paths maps LevelTrace, LevelDebug,
+// LevelInfo, LevelWarn and LevelError to a file path, levels without a path are not written to a file.
+// Files are appended to, levels with the same path share the file. It returns
+// the ApplicationLog like the other Start functions, or the error of the first
+// file that could not be opened without starting the logger.
+func (l *Logger) StartMultiFile(logLevel int32, paths map[int32]string) (*ApplicationLog, error) {
+	files := make(map[int32]io.Writer, len(paths))
+	byPath := make(map[string]*os.File, len(paths))
+	var opened []*os.File
+
+	for level, path := range paths {
+		switch level {
+		case LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError:
+		default:
+			closeFiles(opened)
+			return nil, fmt.Errorf("applogger: unknown level %d for log file %s", level, path)
+		}
+
+		if f, ok := byPath[path]; ok {
+			files[level] = f
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			closeFiles(opened)
+			return nil, err
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			closeFiles(opened)
+			return nil, err
+		}
+
+		byPath[path] = f
+		files[level] = f
+		opened = append(opened, f)
+	}
+
+	// Turn the logging on
+	a := l.start()
+	l.turnOnLevelLogging(logLevel, files)
+
+	a.mu.Lock()
+	a.levelFiles = opened
+	a.mu.Unlock()
+
+	for _, f := range opened {
+		l.preallocate(f)
+	}
+
+	l.startSummary()
+	return a, nil
+}
+
+// LogLevelDirectoryCleanup runs LogDirectoryCleanup for the log directory of
+// every level, so each level can be kept for a different number of days.
+func (l *Logger) LogLevelDirectoryCleanup(configs map[int32]LevelCleanup) {
+	for _, level := range []int32{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		if cfg, ok := configs[level]; ok {
+			l.LogDirectoryCleanup(cfg.BaseFilePath, cfg.DaysToKeep)
+		}
+	}
+}
+
+// closeFiles closes the files opened before StartMultiFile failed
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+// containsWriter reports whether w is in writers
+func containsWriter(writers []io.Writer, w io.Writer) bool {
+	for _, v := range writers {
+		if v == w {
+			return true
+		}
+	}
+	return false
+}
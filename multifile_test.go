@@ -0,0 +1,58 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStartMultiFile(t *testing.T) {
+	dir := tempDir(t)
+	errorPath := filepath.Join(dir, "error", "error.log")
+	infoPath := filepath.Join(dir, "app.log")
+
+	l := &Logger{DisableColor: true}
+	a, err := l.StartMultiFile(LevelDebug, map[int32]string{
+		LevelDebug: infoPath,
+		LevelInfo:  infoPath,
+		LevelError: errorPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == nil || a != l.instance() {
+		t.Fatal("StartMultiFile did not return the ApplicationLog of the logger")
+	}
+
+	l.Info("info line")
+	l.Error("error line")
+	l.Warning("warning line")
+	l.Stop()
+
+	info := readFile(t, infoPath)
+	if !strings.Contains(info, "info line") || strings.Contains(info, "error line") || strings.Contains(info, "warning line") {
+		t.Errorf("info file:\n%s", info)
+	}
+	errors := readFile(t, errorPath)
+	if !strings.Contains(errors, "error line") || strings.Contains(errors, "info line") {
+		t.Errorf("error file:\n%s", errors)
+	}
+}
+
+func TestStartMultiFileError(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{}
+	a, err := l.StartMultiFile(LevelInfo, map[int32]string{LevelFatal: filepath.Join(dir, "fatal.log")})
+	if err == nil || a != nil {
+		t.Errorf("StartMultiFile with an unknown level = %v, %v", a, err)
+	}
+
+	a, err = l.StartMultiFile(LevelInfo, map[int32]string{LevelInfo: dir})
+	if err == nil || a != nil {
+		t.Errorf("StartMultiFile on a directory = %v, %v", a, err)
+	}
+}
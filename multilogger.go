@@ -0,0 +1,323 @@
+package applogger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MultiLogger writes every line to several independently started loggers,
+// e.g. Debug to a local file and Errors to a log aggregator
+type MultiLogger struct {
+	loggers []*Logger
+}
+
+// NewMultiLogger returns a MultiLogger writing to the loggers. The caller
+// of the MultiLogger is written as the caller of the lines. The loggers can
+// be started, stopped and configured after, every call uses them as they are.
+func NewMultiLogger(loggers ...*Logger) *MultiLogger {
+	return &MultiLogger{loggers: loggers}
+}
+
+// skipFrame returns a copy of l that also skips the frame of the MultiLogger
+// method. It is copied at every call, a copy kept would miss the Start of l.
+func skipFrame(l *Logger) *Logger {
+	depth := l.CallerDepth
+	if depth <= 0 {
+		depth = defaultCallerDepth
+	}
+	return l.WithCallerDepth(depth + 1)
+}
+
+// Started uses the Serialize destination of every logger and adds a Started tag to the log line
+func (m *MultiLogger) Started(functionName string) {
+	for _, l := range m.loggers {
+		skipFrame(l).Started(functionName)
+	}
+}
+
+// Startedf uses the Serialize destination of every logger and writes a Started tag to the log line
+func (m *MultiLogger) Startedf(functionName string, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Startedf(functionName, format, a...)
+	}
+}
+
+// Completed uses the Serialize destination of every logger and writes a Completed tag to the log line
+func (m *MultiLogger) Completed(functionName string) {
+	for _, l := range m.loggers {
+		skipFrame(l).Completed(functionName)
+	}
+}
+
+// Completedf uses the Serialize destination of every logger and writes a Completed tag to the log line
+func (m *MultiLogger) Completedf(functionName string, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Completedf(functionName, format, a...)
+	}
+}
+
+// CompletedError uses the Error destination of every logger and writes a Completed tag to the log line
+func (m *MultiLogger) CompletedError(functionName string, err error) {
+	for _, l := range m.loggers {
+		skipFrame(l).CompletedError(functionName, err)
+	}
+}
+
+// CompletedErrorf uses the Error destination of every logger and writes a Completed tag to the log line
+func (m *MultiLogger) CompletedErrorf(functionName string, err error, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).CompletedErrorf(functionName, err, format, a...)
+	}
+}
+
+// Trace writes to the Trace destination of every logger
+func (m *MultiLogger) Trace(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Trace(format, a...)
+	}
+}
+
+// Tracef writes to the Trace destination of every logger and adds the function name to the log line
+func (m *MultiLogger) Tracef(functionName string, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Tracef(functionName, format, a...)
+	}
+}
+
+// TraceFields writes msg and the fields to the Trace destination of every logger
+func (m *MultiLogger) TraceFields(msg string, fields ...Fields) {
+	for _, l := range m.loggers {
+		skipFrame(l).TraceFields(msg, fields...)
+	}
+}
+
+// DumpRequest writes the request to the Trace destination of every logger,
+// each cuts the body at its own MaxBodyLogSize
+func (m *MultiLogger) DumpRequest(r *http.Request) {
+	for _, l := range m.loggers {
+		skipFrame(l).DumpRequest(r)
+	}
+}
+
+// DumpResponse writes the response to the Trace destination of every logger,
+// each cuts the body at its own MaxBodyLogSize
+func (m *MultiLogger) DumpResponse(resp *http.Response) {
+	for _, l := range m.loggers {
+		skipFrame(l).DumpResponse(resp)
+	}
+}
+
+// Debug writes to the Debug destination of every logger
+func (m *MultiLogger) Debug(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Debug(format, a...)
+	}
+}
+
+// DebugCtx writes to the Debug destination of every logger with the ids found in ctx
+func (m *MultiLogger) DebugCtx(ctx context.Context, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).DebugCtx(ctx, format, a...)
+	}
+}
+
+// DebugFields writes msg and the fields to the Debug destination of every logger
+func (m *MultiLogger) DebugFields(msg string, fields ...Fields) {
+	for _, l := range m.loggers {
+		skipFrame(l).DebugFields(msg, fields...)
+	}
+}
+
+// Verbose writes to the Debug destination of every logger, JSON formats write VERBOSE as the level
+func (m *MultiLogger) Verbose(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Verbose(format, a...)
+	}
+}
+
+// Info writes to the Info destination of every logger
+func (m *MultiLogger) Info(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Info(format, a...)
+	}
+}
+
+// InfoCtx writes to the Info destination of every logger with the ids found in ctx
+func (m *MultiLogger) InfoCtx(ctx context.Context, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).InfoCtx(ctx, format, a...)
+	}
+}
+
+// InfoFields writes msg and the fields to the Info destination of every logger
+func (m *MultiLogger) InfoFields(msg string, fields ...Fields) {
+	for _, l := range m.loggers {
+		skipFrame(l).InfoFields(msg, fields...)
+	}
+}
+
+// Warning writes to the Warning destination of every logger
+func (m *MultiLogger) Warning(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Warning(format, a...)
+	}
+}
+
+// Warn is Warning, named after LevelWarn
+func (m *MultiLogger) Warn(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Warn(format, a...)
+	}
+}
+
+// WarningCtx writes to the Warning destination of every logger with the ids found in ctx
+func (m *MultiLogger) WarningCtx(ctx context.Context, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).WarningCtx(ctx, format, a...)
+	}
+}
+
+// WarningFields writes msg and the fields to the Warning destination of every logger
+func (m *MultiLogger) WarningFields(msg string, fields ...Fields) {
+	for _, l := range m.loggers {
+		skipFrame(l).WarningFields(msg, fields...)
+	}
+}
+
+// Error writes to the Error destination of every logger
+func (m *MultiLogger) Error(err string) {
+	for _, l := range m.loggers {
+		skipFrame(l).Error(err)
+	}
+}
+
+// Errorf writes to the Error destination of every logger and accepts an err
+//
+// Deprecated: the err between format and its arguments trips go vet, use ErrorWith.
+func (m *MultiLogger) Errorf(format string, err error, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Errorf(format, err, a...)
+	}
+}
+
+// ErrorWith writes the formatted message followed by err to the Error destination of every logger
+func (m *MultiLogger) ErrorWith(err error, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).ErrorWith(err, format, a...)
+	}
+}
+
+// ErrorG writes to the Error destination of every logger
+func (m *MultiLogger) ErrorG(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).ErrorG(format, a...)
+	}
+}
+
+// ErrorCtx writes to the Error destination of every logger with the ids found in ctx
+func (m *MultiLogger) ErrorCtx(ctx context.Context, format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).ErrorCtx(ctx, format, a...)
+	}
+}
+
+// ErrorFields writes msg and the fields to the Error destination of every logger
+func (m *MultiLogger) ErrorFields(msg string, fields ...Fields) {
+	for _, l := range m.loggers {
+		skipFrame(l).ErrorFields(msg, fields...)
+	}
+}
+
+// Critical writes to the Error destination of every logger, JSON formats write CRITICAL as the level
+func (m *MultiLogger) Critical(format string, a ...interface{}) {
+	for _, l := range m.loggers {
+		skipFrame(l).Critical(format, a...)
+	}
+}
+
+//** PANIC
+
+// Panic writes the PANIC line to every logger, flushes the log files of all
+// of them and panics with the message through the first logger, e.g. with its PanicFunc
+func (m *MultiLogger) Panic(format string, a ...interface{}) {
+	m.panic(fmt.Sprintf(format, a...))
+}
+
+// Panicf is Panic that adds the function name to the log line
+func (m *MultiLogger) Panicf(functionName string, format string, a ...interface{}) {
+	m.panic(fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...)))
+}
+
+// panic writes msg to every logger for the caller of Panic and Panicf
+func (m *MultiLogger) panic(msg string) {
+	if len(m.loggers) == 0 {
+		panic(msg)
+	}
+
+	apps := make([]*ApplicationLog, len(m.loggers))
+	for i, l := range m.loggers {
+		apps[i] = skipFrame(l).panicOutput(msg)
+	}
+	// The panic usually ends the process, the lines must reach the disk first
+	for _, app := range apps {
+		app.flush()
+	}
+	apps[0].panicWith(msg)
+}
+
+//** FATAL
+
+// Fatal writes the Fatal line to every logger, flushes the log files of all
+// of them and exits with status 1 through the first logger, e.g. its ExitFunc
+func (m *MultiLogger) Fatal(format string, a ...interface{}) {
+	m.fatal(fmt.Sprintf(format, a...))
+}
+
+// Fatalf is Fatal that adds the function name to the log line
+func (m *MultiLogger) Fatalf(functionName string, format string, a ...interface{}) {
+	m.fatal(fmt.Sprintf("%s %s", formatFuncName(functionName), fmt.Sprintf(format, a...)))
+}
+
+// fatal writes msg to every logger for the caller of Fatal and Fatalf
+func (m *MultiLogger) fatal(msg string) {
+	if len(m.loggers) == 0 {
+		Default().exit()
+		return
+	}
+
+	apps := make([]*ApplicationLog, len(m.loggers))
+	for i, l := range m.loggers {
+		apps[i] = skipFrame(l).fatalOutput(msg)
+	}
+	for _, app := range apps[1:] {
+		app.flush()
+	}
+	apps[0].exit()
+}
+
+// Stop stops every logger, the errors of the loggers that failed are combined
+func (m *MultiLogger) Stop() error {
+	var errs stopErrors
+	for _, l := range m.loggers {
+		if err := l.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// stopErrors are the errors returned by the loggers of a MultiLogger
+type stopErrors []error
+
+func (e stopErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,169 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMultiLogger(t *testing.T) {
+	var fileBuf, aggregatorBuf bytes.Buffer
+	file := &Logger{DisableColor: true, FileLogLevel: LevelDebug}
+	file.StartWriter(LevelError, &fileBuf)
+	aggregator := &Logger{DisableColor: true, FileLogLevel: LevelError}
+	aggregator.StartWriter(LevelError, &aggregatorBuf)
+
+	m := NewMultiLogger(file, aggregator)
+	m.Debug("Load : Reading [%s]", "a.txt")
+	want := callerLine(t)
+	m.ErrorWith(errors.New("timeout"), "Load : Failed [%s] after %d tries", "a.txt", 3)
+	m.ErrorG("Load : Gave up [%s]", "a.txt")
+	m.Errorf("Load : Failed [%s]", errors.New("closed"), "b.txt")
+
+	for name, out := range map[string]string{"file": fileBuf.String(), "aggregator": aggregatorBuf.String()} {
+		for _, line := range []string{
+			" " + want + ": Load : Failed [a.txt] after 3 tries timeout\n",
+			": Load : Gave up [a.txt]\n",
+			": Load : Failed [b.txt] closed\n",
+		} {
+			if !strings.Contains(out, line) {
+				t.Errorf("%s: %q missing in:\n%s", name, line, out)
+			}
+		}
+	}
+	if !strings.Contains(fileBuf.String(), "Load : Reading [a.txt]") || strings.Contains(aggregatorBuf.String(), "Reading") {
+		t.Errorf("the levels of the loggers were not kept:\nfile:\n%s\naggregator:\n%s", fileBuf.String(), aggregatorBuf.String())
+	}
+}
+
+func TestMultiLoggerStartedLater(t *testing.T) {
+	var firstBuf, secondBuf bytes.Buffer
+	first := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	second := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+
+	m := NewMultiLogger(first, second)
+	first.StartWriter(LevelError, &firstBuf)
+	second.StartWriter(LevelError, &secondBuf)
+
+	m.Info("Serve : Listening")
+	if !strings.Contains(firstBuf.String(), "Serve : Listening") || !strings.Contains(secondBuf.String(), "Serve : Listening") {
+		t.Errorf("loggers started after NewMultiLogger missed the line:\nfirst:\n%s\nsecond:\n%s", firstBuf.String(), secondBuf.String())
+	}
+}
+
+func TestMultiLoggerFatal(t *testing.T) {
+	var firstBuf bytes.Buffer
+	var secondBuf syncBuffer
+
+	var codes []int
+	var flushed string
+	first := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	first.ExitFunc = func(c int) {
+		codes = append(codes, c)
+		flushed = secondBuf.String()
+	}
+	first.StartWriter(LevelError, &firstBuf)
+	second := &Logger{DisableColor: true, FileLogLevel: LevelInfo, Async: true}
+	second.ExitFunc = func(c int) { t.Error("Fatal exited through the second logger") }
+	second.StartWriter(LevelError, &secondBuf)
+
+	m := NewMultiLogger(first, second)
+	second.Info("queued before Fatal")
+	want := callerLine(t)
+	m.Fatal("cannot open %s", "db")
+
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Fatalf("exit codes %v, want [1]", codes)
+	}
+	if !strings.Contains(firstBuf.String(), " "+want+": cannot open db\n") {
+		t.Errorf("the Fatal line is missing in the first logger:\n%s", firstBuf.String())
+	}
+	if !strings.Contains(flushed, "queued before Fatal") || !strings.Contains(flushed, " "+want+": cannot open db\n") {
+		t.Errorf("the second logger was not flushed before the exit:\n%s", flushed)
+	}
+}
+
+func TestMultiLoggerPanic(t *testing.T) {
+	var firstBuf, secondBuf bytes.Buffer
+
+	var panics []interface{}
+	first := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	first.PanicFunc = func(v interface{}) { panics = append(panics, v) }
+	first.StartWriter(LevelError, &firstBuf)
+	second := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	second.PanicFunc = func(v interface{}) { t.Error("Panic panicked through the second logger") }
+	second.StartWriter(LevelError, &secondBuf)
+
+	m := NewMultiLogger(first, second)
+	m.Panicf("Charge", "order %s", "a1")
+
+	if len(panics) != 1 || panics[0] != "Charge() order a1" {
+		t.Fatalf("panics %v", panics)
+	}
+	for name, out := range map[string]string{"first": firstBuf.String(), "second": secondBuf.String()} {
+		if !strings.Contains(out, "Charge() order a1\n") {
+			t.Errorf("%s: the PANIC line is missing:\n%s", name, out)
+		}
+	}
+}
+
+func TestMultiLoggerPanicFlushes(t *testing.T) {
+	var firstBuf bytes.Buffer
+	var secondBuf syncBuffer
+
+	var flushed string
+	first := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	first.PanicFunc = func(v interface{}) { flushed = secondBuf.String() }
+	first.StartWriter(LevelError, &firstBuf)
+	second := &Logger{DisableColor: true, FileLogLevel: LevelInfo, Async: true}
+	second.StartWriter(LevelError, &secondBuf)
+
+	m := NewMultiLogger(first, second)
+	second.Info("queued before Panic")
+	m.Panic("order %s", "a1")
+
+	if !strings.Contains(flushed, "queued before Panic") || !strings.Contains(flushed, "order a1\n") {
+		t.Errorf("the second logger was not flushed before the panic:\n%s", flushed)
+	}
+}
+
+func TestMultiLoggerFields(t *testing.T) {
+	var fileBuf, aggregatorBuf bytes.Buffer
+	file := &Logger{DisableColor: true, FileLogLevel: LevelTrace}
+	file.StartWriter(LevelError, &fileBuf)
+	aggregator := &Logger{DisableColor: true, FileLogLevel: LevelWarn}
+	aggregator.StartWriter(LevelError, &aggregatorBuf)
+
+	m := NewMultiLogger(file, aggregator)
+	m.InfoFields("Charge : Completed", StringField("order", "a1"))
+	want := callerLine(t)
+	m.ErrorFields("Charge : Failed", IntField("tries", 3))
+	m.DumpRequest(httptest.NewRequest("GET", "/orders/a1", nil))
+
+	if out := fileBuf.String(); !strings.Contains(out, "Charge : Completed order=a1\n") || !strings.Contains(out, "GET /orders/a1 HTTP/1.1") {
+		t.Errorf("the file logger is missing lines:\n%s", out)
+	}
+	out := aggregatorBuf.String()
+	if strings.Contains(out, "Completed") || strings.Contains(out, "DumpRequest") {
+		t.Errorf("the aggregator wrote lines below its level:\n%s", out)
+	}
+	if !strings.Contains(out, " "+want+": Charge : Failed tries=3\n") {
+		t.Errorf("the ErrorFields line is missing in the aggregator:\n%s", out)
+	}
+}
+
+func TestMultiLoggerStop(t *testing.T) {
+	first := &Logger{DisableColor: true}
+	first.StartWriter(LevelError, &bytes.Buffer{})
+	second := &Logger{DisableColor: true}
+	second.StartWriter(LevelError, &bytes.Buffer{})
+
+	if err := NewMultiLogger(first, second).Stop(); err != nil {
+		t.Errorf("Stop = %v", err)
+	}
+}
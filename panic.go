@@ -0,0 +1,57 @@
+package applogger
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// CapturePanics runs fn and, if it panics, writes an Error entry with the
+// panic value in the "panic" field and the stack trace in the "stack" field
+// before panicking again with the same value.
+func (l *Logger) CapturePanics(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.write(LevelError, 1, "CapturePanics() : panic recovered",
+				Field{Key: "panic", Value: fmt.Sprint(r)},
+				Field{Key: "stack", Value: string(debug.Stack())},
+			)
+
+			// Make sure the entry reaches the disk before the process crashes,
+			// the same way Fatal does.
+			l.instance().flush()
+
+			panic(r)
+		}
+	}()
+
+	fn()
+}
+
+// Go runs fn in a new goroutine that recovers a panic and writes it as an
+// Error entry instead of crashing the process.
+func (l *Logger) Go(fn func()) {
+	l.GoNamed("", fn)
+}
+
+// GoNamed is Go with a name for the goroutine, written in the "goroutine"
+// field next to the "panic" and "stack" fields.
+func (l *Logger) GoNamed(name string, fn func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fields := []Field{
+					{Key: "panic", Value: fmt.Sprint(r)},
+					{Key: "stack", Value: string(debug.Stack())},
+				}
+				msg := "Go() : panic recovered"
+				if name != "" {
+					msg = fmt.Sprintf("GoNamed() : panic recovered [%s]", name)
+					fields = append([]Field{{Key: "goroutine", Value: name}}, fields...)
+				}
+				l.write(LevelError, 1, msg, fields...)
+			}
+		}()
+
+		fn()
+	}()
+}
@@ -0,0 +1,94 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPanicFunc(t *testing.T) {
+	var buf bytes.Buffer
+	var panics []interface{}
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.PanicFunc = func(v interface{}) { panics = append(panics, v) }
+	l.StartWriter(LevelError, &buf)
+
+	l.Panic("cannot %s", "continue")
+	l.Panicf("handler", "bad state %d", 3)
+
+	if len(panics) != 2 || panics[0] != "cannot continue" || panics[1] != "handler() bad state 3" {
+		t.Errorf("panic values %q", panics)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "ERROR: ") || !strings.Contains(out, ": cannot continue\n") || !strings.Contains(out, ": handler() bad state 3\n") {
+		t.Errorf("unexpected lines:\n%s", out)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	defer func() {
+		if r := recover(); r != "cannot continue" {
+			t.Errorf("recovered %v", r)
+		}
+		if !strings.Contains(buf.String(), ": cannot continue\n") {
+			t.Errorf("the line was not written before the panic:\n%s", buf.String())
+		}
+	}()
+	l.Panic("cannot continue")
+}
+
+func TestCapturePanicsAsync(t *testing.T) {
+	dir := tempDir(t)
+	l := &Logger{DisableColor: true, Async: true, FlushInterval: time.Hour}
+	l.StartFile(LevelError, dir, 1)
+	defer l.Stop()
+
+	defer func() {
+		if r := recover(); r != "broken" {
+			t.Errorf("recovered %v", r)
+		}
+		if out := readLogFile(t, dir); !strings.Contains(out, "CapturePanics() : panic recovered") || !strings.Contains(out, "panic=broken") {
+			t.Errorf("the queued entry was not written before the panic:\n%s", out)
+		}
+	}()
+	l.CapturePanics(func() { panic("broken") })
+}
+
+func TestGinRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatJSON, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	r := gin.New()
+	r.Use(l.GinRecovery())
+	r.GET("/users/:id", func(c *gin.Context) { l.Panic("user %s not loaded", c.Param("id")) })
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "internal server error") {
+		t.Errorf("response %d %s", rec.Code, rec.Body.String())
+	}
+	out := buf.String()
+	for _, want := range []string{`"panic":"user 7 not loaded"`, `"route":"/users/:id"`, `"request_id":"abc"`, `"stack":"goroutine `} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s missing in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package applogger
+
+import "os"
+
+// preallocate reserves PreallocateBytes of disk space for a new log file.
+// The file size is kept so appends still start at the end of the written data.
+func (l *Logger) preallocate(f *os.File) {
+	if l.PreallocateBytes <= 0 {
+		return
+	}
+
+	if err := fallocate(f, l.PreallocateBytes); err != nil {
+		l.ErrorWith(err, "preallocate : Failed to Preallocate log file [%s] :", f.Name())
+	}
+}
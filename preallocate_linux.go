@@ -0,0 +1,17 @@
+//go:build linux
+// +build linux
+
+package applogger
+
+import (
+	"os"
+	"syscall"
+)
+
+// fallocateKeepSize is FALLOC_FL_KEEP_SIZE, the space is allocated without growing the file
+const fallocateKeepSize = 0x1
+
+// fallocate allocates size bytes of disk space for f
+func fallocate(f *os.File, size int64) error {
+	return syscall.Fallocate(int(f.Fd()), fallocateKeepSize, 0, size)
+}
@@ -0,0 +1,116 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+const testPreallocateBytes = 4 * 1024 * 1024
+
+// allocated returns the disk space allocated for the file at path
+func allocated(t *testing.T, path string) (size, reserved int64) {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size(), info.Sys().(*syscall.Stat_t).Blocks * 512
+}
+
+func TestPreallocate(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{DisableColor: true, PreallocateBytes: testPreallocateBytes}
+	a, err := l.TryStartFile(LevelInfo, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	l.Info("preallocated")
+	size, reserved := allocated(t, a.LogFile.Name())
+	if reserved < testPreallocateBytes {
+		t.Skipf("the file system did not reserve the space, %d bytes allocated", reserved)
+	}
+	if size >= testPreallocateBytes {
+		t.Errorf("the file grew to %d bytes, appends no longer start at the written data", size)
+	}
+	if content := readFile(t, a.LogFile.Name()); !strings.HasSuffix(content, "preallocated\n") {
+		t.Errorf("unexpected content:\n%q", content)
+	}
+}
+
+func TestPreallocateRotation(t *testing.T) {
+	dir := tempDir(t)
+
+	// The long line is only written to the file
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo, PreallocateBytes: testPreallocateBytes}
+	a := l.StartFileWithRotation(LevelError, dir, 1, 1)
+	defer l.Stop()
+
+	first := a.LogFile.Name()
+	l.Info("%s", strings.Repeat("x", 1024*1024))
+	if a.LogFile.Name() == first {
+		t.Fatal("the log file was not rotated")
+	}
+
+	if _, reserved := allocated(t, first); reserved < testPreallocateBytes {
+		t.Skipf("the file system did not reserve the space, %d bytes allocated", reserved)
+	}
+	if size, reserved := allocated(t, a.LogFile.Name()); reserved < testPreallocateBytes || size >= testPreallocateBytes {
+		t.Errorf("rotated file has %d bytes written and %d bytes allocated", size, reserved)
+	}
+}
+
+func TestPreallocateDisabled(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{DisableColor: true}
+	a, err := l.TryStartFile(LevelInfo, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	if _, reserved := allocated(t, a.LogFile.Name()); reserved >= testPreallocateBytes {
+		t.Errorf("%d bytes allocated without PreallocateBytes", reserved)
+	}
+}
+
+func BenchmarkFileWrite(b *testing.B) {
+	for _, bm := range []struct {
+		name        string
+		preallocate int64
+	}{
+		{"Default", 0},
+		{"Preallocated", 64 * 1024 * 1024},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			dir, err := ioutil.TempDir("", "applogger")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			// Only the file write is measured, the console logs errors only
+			l := &Logger{DisableColor: true, FileLogLevel: LevelInfo, PreallocateBytes: bm.preallocate}
+			if _, err := l.TryStartFile(LevelError, dir, 1); err != nil {
+				b.Fatal(err)
+			}
+			defer l.Stop()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info("BenchmarkFileWrite : Completed [%d]", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package applogger
+
+import "os"
+
+// fallocate is a no-op where fallocate is not available
+func fallocate(f *os.File, size int64) error {
+	return nil
+}
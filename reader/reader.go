@@ -0,0 +1,174 @@
+// Package reader reads JSONL log files, one JSON object per entry.
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLineSize is the longest JSONL line the reader accepts
+const maxLineSize = 1024 * 1024
+
+// headerKey marks the header line of WriteFileHeader, it is not an entry
+const headerKey = "log_header"
+
+// Entry is a log line with the level, timestamp, caller, function and message keys
+type Entry struct {
+	Level     string
+	Timestamp time.Time
+	Caller    string
+	Function  string
+	Message   string
+
+	// Fields are the keys of the line other than the ones above
+	Fields map[string]interface{}
+
+	// Line is the JSON line as read, without the newline
+	Line []byte
+}
+
+// FieldKeys returns the keys of Fields in sorted order
+func (e *Entry) FieldKeys() []string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// JSONLReader reads the entries of a JSONL log stream one at a time.
+// Empty lines and the header lines of WriteFileHeader are skipped.
+type JSONLReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewJSONLReader returns a reader of the entries in r
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	return &JSONLReader{scanner: scanner}
+}
+
+// Next returns the next entry of the stream, io.EOF at the end of it.
+// A line that is not a JSON object is an error naming its line number.
+func (r *JSONLReader) Next() (*Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return nil, fmt.Errorf("reader: line %d: %s", r.line, err)
+		}
+		if fields[headerKey] == true {
+			continue
+		}
+
+		entry := &Entry{Line: append([]byte(nil), line...)}
+		entry.Level = popString(fields, "level")
+		entry.Caller = popString(fields, "caller")
+		entry.Function = popString(fields, "function")
+		entry.Message = popString(fields, "message")
+		if ts := popString(fields, "timestamp"); ts != "" {
+			t, err := time.Parse(time.RFC3339Nano, ts)
+			if err != nil {
+				return nil, fmt.Errorf("reader: line %d: %s", r.line, err)
+			}
+			entry.Timestamp = t
+		}
+		entry.Fields = fields
+		return entry, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// popString removes key from fields and returns its value when it is a string
+func popString(fields map[string]interface{}, key string) string {
+	s, ok := fields[key].(string)
+	if ok {
+		delete(fields, key)
+	}
+	return s
+}
+
+// severities orders the level names and their aliases
+var severities = map[string]int{
+	"trace":    0,
+	"debug":    1,
+	"verbose":  1,
+	"info":     2,
+	"warn":     3,
+	"warning":  3,
+	"error":    4,
+	"critical": 4,
+	"fatal":    5,
+}
+
+// Severity returns the rank of a level name, higher is more severe. It
+// reports false for names that are not levels, e.g. the LevelName of a
+// custom level.
+func Severity(level string) (int, bool) {
+	s, ok := severities[strings.ToLower(level)]
+	return s, ok
+}
+
+// Filter selects entries, the zero value matches every entry
+type Filter struct {
+	// Level is the least severe level matched, e.g. warn matches warnings,
+	// errors and fatal lines. Entries with unknown levels are left out.
+	Level string
+
+	// Since and Until bound the timestamps of the entries, Until excluded
+	Since time.Time
+	Until time.Time
+
+	// Caller is matched against the caller file:line and the function
+	Caller string
+
+	// MessageContains is a substring of the message
+	MessageContains string
+}
+
+// Match reports whether e passes every condition of the filter
+func (f *Filter) Match(e *Entry) bool {
+	if f.Level != "" {
+		min, ok := Severity(f.Level)
+		level, known := Severity(e.Level)
+		if !ok || !known || level < min {
+			return false
+		}
+	}
+
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Timestamp.Before(f.Until) {
+		return false
+	}
+
+	if f.Caller != "" && !strings.Contains(e.Caller, f.Caller) && !strings.Contains(e.Function, f.Caller) {
+		return false
+	}
+	if f.MessageContains != "" && !strings.Contains(e.Message, f.MessageContains) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package reader
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLReader(t *testing.T) {
+	log := `{"log_header":true,"host":"api-1"}
+{"level":"info","timestamp":"2026-01-02T15:04:05.123Z","caller":"reader_test.go:12","message":"Load : Started"}
+
+{"level":"warning","timestamp":"2026-01-02T15:04:06Z","caller":"reader_test.go:13","message":"Load : Slow","ms":1500}
+`
+	r := NewJSONLReader(strings.NewReader(log))
+
+	first, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Level != "info" || first.Message != "Load : Started" || first.Timestamp.IsZero() {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	if !strings.HasPrefix(first.Caller, "reader_test.go:") {
+		t.Errorf("caller %q", first.Caller)
+	}
+
+	second, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Level != "warning" || second.Fields["ms"] == nil {
+		t.Errorf("unexpected second entry %+v", second)
+	}
+	if _, ok := second.Fields["message"]; ok {
+		t.Error("the fixed keys are left in Fields")
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next at the end = %v, want io.EOF", err)
+	}
+}
+
+func TestJSONLReaderInvalidLine(t *testing.T) {
+	r := NewJSONLReader(strings.NewReader("\n{\"message\":\"ok\"}\nnot json\n"))
+	if _, err := r.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := r.Next()
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Next on an invalid line = %v", err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	entry := &Entry{
+		Level:     "warning",
+		Timestamp: now,
+		Caller:    "orders.go:42",
+		Function:  "Checkout",
+		Message:   "Checkout : Payment retried",
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{"zero", Filter{}, true},
+		{"level below", Filter{Level: "info"}, true},
+		{"level equal", Filter{Level: "warn"}, true},
+		{"level above", Filter{Level: "error"}, false},
+		{"since", Filter{Since: now}, true},
+		{"since after", Filter{Since: now.Add(time.Second)}, false},
+		{"until", Filter{Until: now.Add(time.Second)}, true},
+		{"until excluded", Filter{Until: now}, false},
+		{"caller file", Filter{Caller: "orders.go"}, true},
+		{"caller function", Filter{Caller: "Checkout"}, true},
+		{"caller other", Filter{Caller: "users.go"}, false},
+		{"message", Filter{MessageContains: "Payment"}, true},
+		{"message other", Filter{MessageContains: "Refund"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(entry); got != tt.match {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.match)
+		}
+	}
+
+	custom := &Entry{Level: "audit"}
+	if (&Filter{Level: "trace"}).Match(custom) {
+		t.Error("a custom level matched a level filter")
+	}
+}
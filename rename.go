@@ -0,0 +1,120 @@
+package applogger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileSwitcher is the writer holding the log file of StartFile,
+// StartFileWithRotation and StartFileHourly
+type fileSwitcher interface {
+	io.Writer
+
+	// switchFile continues the log in next and makes it the LogFile, unless
+	// current is no longer the file being written, e.g. after a rotation.
+	// It reports whether it switched.
+	switchFile(current, next *os.File) bool
+}
+
+// logFile writes to the log file of StartFile
+type logFile struct {
+	a *ApplicationLog
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Write writes p to the log file
+func (f *logFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Write(p)
+}
+
+// switchFile implements fileSwitcher
+func (f *logFile) switchFile(current, next *os.File) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file != current {
+		return false
+	}
+	f.file = next
+
+	f.a.mu.Lock()
+	f.a.LogFile = next
+	f.a.mu.Unlock()
+	return true
+}
+
+// DetectFileRename checks every interval whether the log file opened by StartFile
+// was renamed or removed, e.g. by logrotate without copytruncate, and reopens a
+// new file at the original path when it was. Stop ends the checks.
+// It returns ErrInvalidInterval for an interval of 0 or less.
+func (l *Logger) DetectFileRename(interval time.Duration) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	l.pending().background(func(stopped <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopped:
+				return
+			case <-ticker.C:
+				l.reopenIfRenamed()
+			}
+		}
+	})
+	return nil
+}
+
+// reopenIfRenamed reopens the log file when its path no longer points to the
+// open file. Only the file is replaced, the level writers built by Start, e.g.
+// the file lock, the rotation and the outputs set with SetOutput, are kept.
+func (l *Logger) reopenIfRenamed() {
+	a := l.instance()
+
+	a.mu.RLock()
+	file := a.LogFile
+	out := a.logOut
+	a.mu.RUnlock()
+
+	if file == nil || out == nil {
+		return
+	}
+
+	current, err := file.Stat()
+	if err != nil {
+		return
+	}
+
+	onDisk, err := os.Stat(file.Name())
+	if err == nil && os.SameFile(current, onDisk) {
+		return
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	reopened, err := os.OpenFile(file.Name(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		l.ErrorWith(err, "DetectFileRename : Failed to Reopen log file [%s] :", file.Name())
+		return
+	}
+	l.continueFile(reopened, a.LogLevel())
+
+	// The file was rotated in the meantime, the new one is written already
+	if !out.switchFile(file, reopened) {
+		reopened.Close()
+		return
+	}
+	file.Close()
+
+	l.Info("DetectFileRename : Reopened log file [%s]", file.Name())
+}
@@ -0,0 +1,100 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDetectFileRenameInterval(t *testing.T) {
+	l := Discard()
+	if err := l.DetectFileRename(0); err != ErrInvalidInterval {
+		t.Errorf("DetectFileRename(0) = %v, want ErrInvalidInterval", err)
+	}
+	if err := l.DetectFileRename(-1); err != ErrInvalidInterval {
+		t.Errorf("DetectFileRename(-1) = %v, want ErrInvalidInterval", err)
+	}
+}
+
+func TestReopenIfRenamed(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{DisableColor: true}
+	a, err := l.TryStartFile(LevelInfo, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+	l.EnableFileLock()
+
+	var errors bytes.Buffer
+	if err := l.SetOutput(LevelError, &errors); err != nil {
+		t.Fatal(err)
+	}
+
+	path := a.LogFile.Name()
+	l.Info("before rename")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+
+	l.reopenIfRenamed()
+	if a.LogFile.Name() != path {
+		t.Fatalf("LogFile is %s, want %s", a.LogFile.Name(), path)
+	}
+
+	l.Info("after rename")
+	l.Error("still redirected")
+
+	rotated := readFile(t, path+".1")
+	if !strings.Contains(rotated, "before rename") || strings.Contains(rotated, "after rename") {
+		t.Errorf("renamed file:\n%s", rotated)
+	}
+	if current := readFile(t, path); !strings.Contains(current, "after rename") {
+		t.Errorf("reopened file misses the new lines:\n%s", current)
+	}
+	if !strings.Contains(errors.String(), "still redirected") {
+		t.Errorf("the SetOutput redirect was lost, got %q", errors.String())
+	}
+}
+
+func TestReopenIfRenamedWithRotation(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{DisableColor: true}
+	a := l.StartFileWithRotation(LevelInfo, dir, 1, 1)
+	defer l.Stop()
+
+	path := a.LogFile.Name()
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	l.reopenIfRenamed()
+	l.Info("after removal")
+
+	if current := readFile(t, path); !strings.Contains(current, "after removal") {
+		t.Errorf("reopened file misses the new lines:\n%s", current)
+	}
+}
+
+func TestReopenIfRenamedUnchanged(t *testing.T) {
+	dir := tempDir(t)
+
+	l := &Logger{DisableColor: true}
+	a, err := l.TryStartFile(LevelInfo, dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Stop()
+
+	file := a.LogFile
+	l.reopenIfRenamed()
+	if a.LogFile != file {
+		t.Error("the file was reopened although it was not renamed")
+	}
+}
@@ -0,0 +1,46 @@
+package applogger
+
+// RequireFields returns a copy of the logger that writes a Warning
+// "missing required log field: <key>" after every entry written through it
+// without one of the keys, e.g. request_id in a multi-tenant service.
+// Fields added by the transforms count as present.
+func (l *Logger) RequireFields(keys ...string) *Logger {
+	derived := *l
+	derived.required = append(append([]string(nil), l.required...), keys...)
+	return &derived
+}
+
+// outputRequired is output for loggers with required fields
+func (l *Logger) outputRequired(level int32, levelName string, calldepth int, msg string, fields ...Field) error {
+	a := l.instance()
+
+	entry := a.entry(level, levelName, calldepth+1, msg, fields...)
+	if entry == nil {
+		return nil
+	}
+	err := a.emit(entry, calldepth+1)
+
+	for _, key := range missingFields(l.withFields(entry.Fields), l.required) {
+		msg, _ := l.redacted("missing required log field: "+key, nil)
+		a.output(LevelWarn, "", calldepth+1, msg)
+	}
+	return err
+}
+
+// missingFields returns the keys that are not in fields
+func missingFields(fields []Field, keys []string) []string {
+	var missing []string
+	for _, key := range keys {
+		found := false
+		for _, f := range fields {
+			if f.Key == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
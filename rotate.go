@@ -0,0 +1,105 @@
+package applogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// rotatingFile writes to the log file opened by StartFileWithRotation and
+// continues in a new file with the next sequence suffix once the file
+// reaches maxBytes, e.g. 2006-01-02T15-04-05.txt continues in
+// 2006-01-02T15-04-05_001.txt. A line is never split, so a file can
+// exceed maxBytes by the last line written to it.
+type rotatingFile struct {
+	l        *Logger
+	logLevel int32
+	maxBytes int64
+	// base is the path of the first file without its extension ext
+	base string
+	ext  string
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	sequence int
+}
+
+// Write writes p to the current file and rotates once the file is full
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+
+	if r.size >= r.maxBytes {
+		r.rotate()
+	}
+	return n, nil
+}
+
+// rotate switches to the next file. It runs while a line is being written,
+// so failures are reported through OnWriteError instead of the log.
+func (r *rotatingFile) rotate() {
+	a := r.l.instance()
+
+	name := fmt.Sprintf("%s_%03d%s", r.base, r.sequence+1, r.ext)
+	next, err := os.Create(name)
+	if err != nil {
+		a.writeFailed(LevelError, fmt.Errorf("applogger: rotate log file %s: %s", name, err))
+		return
+	}
+
+	r.l.continueFile(next, r.logLevel)
+
+	r.file.Close()
+	r.file = next
+	r.size = 0
+	r.sequence++
+
+	a.mu.Lock()
+	a.LogFile = next
+	a.mu.Unlock()
+}
+
+// switchFile implements fileSwitcher, the log continues in next with the
+// current sequence
+func (r *rotatingFile) switchFile(current, next *os.File) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != current {
+		return false
+	}
+	r.file = next
+	r.size = 0
+
+	a := r.l.instance()
+	a.mu.Lock()
+	a.LogFile = next
+	a.mu.Unlock()
+	return true
+}
+
+// continueFile writes the headers of a file the log continues in and reserves
+// its disk space. It runs while a line is being written, so failures are
+// reported through OnWriteError instead of the log.
+func (l *Logger) continueFile(next *os.File, logLevel int32) {
+	if l.WriteFileHeader {
+		l.writeFileHeader(next, logLevel)
+	}
+	if l.Format == FormatW3CExtended {
+		io.WriteString(next, w3cHeader(time.Now()))
+	}
+	if l.PreallocateBytes > 0 {
+		if err := fallocate(next, l.PreallocateBytes); err != nil {
+			l.instance().writeFailed(LevelError, err)
+		}
+	}
+}
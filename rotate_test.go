@@ -0,0 +1,92 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStartFileWithRotation(t *testing.T) {
+	dir := tempDir(t)
+
+	// The lines only go to the file
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	a := l.StartFileWithRotation(LevelError, dir, 1, 1)
+	first := a.LogFile.Name()
+
+	const (
+		writers = 8
+		lines   = 500
+		maxSize = 1024 * 1024
+	)
+	payload := strings.Repeat("x", 1000)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				l.Info("Write : %s", payload)
+			}
+		}()
+	}
+	wg.Wait()
+	l.Stop()
+
+	base := strings.TrimSuffix(first, filepath.Ext(first))
+	paths, err := filepath.Glob(base + "*" + filepath.Ext(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) < 3 {
+		t.Fatalf("%d files for %d lines of 1KB, want at least 3: %v", len(paths), writers*lines, paths)
+	}
+	if paths[0] != first || paths[1] != base+"_001"+filepath.Ext(first) {
+		t.Errorf("unexpected file names %v", paths)
+	}
+
+	total := 0
+	for i, path := range paths {
+		content := readFile(t, path)
+		total += strings.Count(content, "\n")
+
+		// A file is closed on the first line past the limit and a line is never split
+		if i < len(paths)-1 && (len(content) < maxSize || len(content) > maxSize+1100) {
+			t.Errorf("%s holds %d bytes, want %d plus at most a line", path, len(content), maxSize)
+		}
+		if i == len(paths)-1 && len(content) > maxSize+1100 {
+			t.Errorf("the last file holds %d bytes", len(content))
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			if !strings.HasSuffix(line, payload) || !strings.HasPrefix(line, "INFO: ") {
+				t.Fatalf("%s holds a split or mixed line %.80q", path, line)
+			}
+		}
+	}
+	if total != writers*lines {
+		t.Errorf("%d lines written, want %d", total, writers*lines)
+	}
+}
+
+func TestStartFileWithRotationCleanup(t *testing.T) {
+	dir := tempDir(t)
+	writeLogs(t, dir, 10)
+
+	l := &Logger{DisableColor: true}
+	l.StartFileWithRotation(LevelError, dir, 5, 1)
+	defer l.Stop()
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 {
+		t.Errorf("the directory older than daysToKeep was kept: %d entries", len(infos))
+	}
+}
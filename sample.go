@@ -0,0 +1,168 @@
+package applogger
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// callerSampler decides which calls of a call site are written
+type callerSampler struct {
+	mu sync.Mutex
+
+	// rate is the share of the calls SampleAt writes, auto sampled sites have none
+	rate   float64
+	fixed  bool
+	credit float64
+
+	// second and calls count the calls of the current second for AutoSample
+	second int64
+	calls  float64
+}
+
+// SampleAt writes only rate, 0.0 to 1.0, of the calls made at the call site
+// callerKey, e.g. "service/user.go:142" or "user.go:142". The sampling is kept
+// per call site for the whole process, the returned logger is l.
+func (l *Logger) SampleAt(callerKey string, rate float64) *Logger {
+	rate = math.Max(0, math.Min(1, rate))
+	a := l.pending()
+
+	// the first call is written
+	a.samplers.Store(sampleKey(callerKey), &callerSampler{rate: rate, fixed: true, credit: 1 - rate})
+	atomic.StoreInt32(&a.sampling, 1)
+	return l
+}
+
+// AutoSample starts sampling every call site that is called more than maxRate
+// times a second: the first maxRate calls of each second are written and the
+// rest are dropped. Call sites set with SampleAt keep their rate, 0 turns it off.
+func (l *Logger) AutoSample(maxRate float64) {
+	a := l.pending()
+	atomic.StoreUint64(&a.autoSample, math.Float64bits(math.Max(0, maxRate)))
+	atomic.StoreInt32(&a.sampling, 1)
+}
+
+// sampled reports whether the call made at file and line is written
+func (a *ApplicationLog) sampled(file string, line int) bool {
+	if atomic.LoadInt32(&a.sampling) == 0 {
+		return true
+	}
+
+	file = filepath.ToSlash(file)
+	base := path.Base(file)
+	lineKey := ":" + strconv.Itoa(line)
+	dirKey := path.Base(path.Dir(file)) + "/" + base + lineKey
+
+	maxRate := math.Float64frombits(atomic.LoadUint64(&a.autoSample))
+
+	if s, ok := a.samplers.Load(dirKey); ok {
+		return s.(*callerSampler).allow(maxRate)
+	}
+	if s, ok := a.samplers.Load(base + lineKey); ok {
+		return s.(*callerSampler).allow(maxRate)
+	}
+
+	if maxRate <= 0 {
+		return true
+	}
+
+	s, _ := a.samplers.LoadOrStore(dirKey, &callerSampler{})
+	return s.(*callerSampler).allow(maxRate)
+}
+
+// allow reports whether the next call is written, maxRate is the AutoSample rate
+func (s *callerSampler) allow(maxRate float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fixed {
+		s.credit += s.rate
+		if s.credit >= 1 {
+			s.credit--
+			return true
+		}
+		return false
+	}
+
+	// AutoSample was turned off after the site was sampled
+	if maxRate <= 0 {
+		return true
+	}
+
+	if now := time.Now().Unix(); now != s.second {
+		s.second = now
+		s.calls = 0
+	}
+	s.calls++
+	return s.calls <= maxRate
+}
+
+// sampleKey normalizes a call site to dir/file.go:line, or file.go:line without a directory
+func sampleKey(callerKey string) string {
+	callerKey = filepath.ToSlash(callerKey)
+
+	dir, base := path.Split(callerKey)
+	if dir == "" {
+		return base
+	}
+	return path.Base(dir) + "/" + base
+}
+
+// sampleRand is the random source of SampleRate, shared by every logger
+var (
+	sampleMu   sync.Mutex
+	sampleRand = rand.New(rand.NewSource(randomSeed()))
+)
+
+// randomSeed returns a seed read from crypto/rand, or the time when it fails
+func randomSeed() int64 {
+	var seed int64
+	if err := binary.Read(crand.Reader, binary.LittleEndian, &seed); err != nil {
+		return time.Now().UnixNano()
+	}
+	return seed
+}
+
+// sampleFloat returns a random number in [0.0, 1.0)
+func sampleFloat() float64 {
+	sampleMu.Lock()
+	defer sampleMu.Unlock()
+	return sampleRand.Float64()
+}
+
+// SampleN returns a logger that writes the first and then every nth call of
+// Debug, Info, Warning and Error made at the same call site, n < 2 writes
+// every call. The calls are counted by call site rather than by message, so
+// messages built at run time do not grow the counts without bound. The counts
+// are shared by the loggers derived from the returned logger.
+func (l *Logger) SampleN(n int) *Logger {
+	derived := *l
+	derived.sampleN = 0
+	derived.sites = nil
+	if n > 1 {
+		derived.sampleN = uint64(n)
+		derived.sites = new(sync.Map)
+	}
+	return &derived
+}
+
+// sampledCall reports whether the current call is written by SampleN, it is
+// called by the level functions so the call site is 3 frames up
+func (l *Logger) sampledCall() bool {
+	if l.sampleN < 2 {
+		return true
+	}
+
+	var pc [1]uintptr
+	runtime.Callers(l.callerDepth(3), pc[:])
+	count, _ := l.sites.LoadOrStore(pc[0], new(uint64))
+	return (atomic.AddUint64(count.(*uint64), 1)-1)%l.sampleN == 0
+}
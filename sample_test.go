@@ -0,0 +1,92 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSampleRate(t *testing.T) {
+	const calls, rate = 20000, 0.3
+
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo, SampleRate: rate}
+	l.StartWriter(LevelError, &buf)
+
+	for i := 0; i < calls; i++ {
+		l.Info("request %d", i)
+	}
+
+	// 5 standard deviations of the binomial count, failing once in millions of runs
+	written := float64(strings.Count(buf.String(), "\n"))
+	margin := 5 * math.Sqrt(calls*rate*(1-rate))
+	if math.Abs(written-calls*rate) > margin {
+		t.Errorf("%v of %d calls written, want %v ± %.0f", written, calls, calls*rate, margin)
+	}
+}
+
+func TestSampleRateBounds(t *testing.T) {
+	for _, rate := range []float64{0, 1} {
+		var buf bytes.Buffer
+		l := &Logger{FileLogLevel: LevelInfo, SampleRate: rate}
+		l.StartWriter(LevelError, &buf)
+
+		for i := 0; i < 100; i++ {
+			l.Info("request %d", i)
+		}
+		if written := strings.Count(buf.String(), "\n"); written != 100 {
+			t.Errorf("SampleRate %v wrote %d of 100 calls", rate, written)
+		}
+	}
+}
+
+func TestSampleN(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	sampled := l.SampleN(3)
+	for i := 0; i < 10; i++ {
+		sampled.Warning("retry %d", i)
+		sampled.Info("other format %d", i)
+	}
+	l.Warning("retry %d", 100)
+
+	out := buf.String()
+	for _, want := range []string{"retry 0\n", "retry 3\n", "retry 6\n", "retry 9\n", "other format 0\n", "other format 9\n", "retry 100\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q missing in:\n%s", want, out)
+		}
+	}
+	if written := strings.Count(out, "\n"); written != 9 {
+		t.Errorf("%d lines written, want 9:\n%s", written, out)
+	}
+}
+
+func TestSampleNCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelError, &buf)
+
+	sampled := l.SampleN(5)
+	for i := 0; i < 1000; i++ {
+		sampled.Error(fmt.Sprintf("user %d failed", i))
+	}
+
+	if written := strings.Count(buf.String(), "\n"); written != 200 {
+		t.Errorf("%d lines written, want 200", written)
+	}
+	sites := 0
+	sampled.sites.Range(func(key, value interface{}) bool {
+		sites++
+		return true
+	})
+	if sites != 1 {
+		t.Errorf("%d counts kept for a single call site", sites)
+	}
+}
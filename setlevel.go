@@ -0,0 +1,170 @@
+package applogger
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// allLevels are the level bits SetLevel accepts
+const allLevels = LevelTrace | LevelDebug | LevelInfo | LevelWarn | LevelError | LevelFatal
+
+// SetLevel changes the level logged from now on without starting the logger
+// again, it is safe to call while other goroutines are logging. The levels
+// keep writing to the console and files they were started with, writers set
+// with SetOutput are replaced. The level of a child logger set with SetLevel
+// is kept by the CascadeSetLevel of its parents.
+func (l *Logger) SetLevel(level int32) error {
+	if err := l.setLevel(level); err != nil {
+		return err
+	}
+
+	atomic.StoreInt32(&l.instance().levelOverridden, 1)
+	return nil
+}
+
+// AddChild makes child a child of the logger, CascadeSetLevel sets its level
+func (l *Logger) AddChild(child *Logger) {
+	a := l.pending()
+
+	a.mu.Lock()
+	a.children = append(append([]*Logger(nil), a.children...), child)
+	a.mu.Unlock()
+}
+
+// CascadeSetLevel is SetLevel that also sets the level of the children added
+// with AddChild, and of their children, e.g. to switch every subsystem to
+// debug from an admin endpoint. Children given their own level with SetLevel
+// keep it, as do their children, until ResetLevelOverride is called.
+func (l *Logger) CascadeSetLevel(level int32) error {
+	if err := l.setLevel(level); err != nil {
+		return err
+	}
+
+	visited := map[*ApplicationLog]bool{l.instance(): true}
+	return l.cascadeLevel(level, visited)
+}
+
+// ResetLevelOverride lets CascadeSetLevel set the level again after SetLevel
+func (l *Logger) ResetLevelOverride() {
+	atomic.StoreInt32(&l.instance().levelOverridden, 0)
+}
+
+// cascadeLevel sets the level of the children that are not overridden,
+// visited stops the walk at loggers already set
+func (l *Logger) cascadeLevel(level int32, visited map[*ApplicationLog]bool) error {
+	a := l.instance()
+
+	a.mu.RLock()
+	children := a.children
+	a.mu.RUnlock()
+
+	for _, child := range children {
+		ca := child.instance()
+		if visited[ca] || atomic.LoadInt32(&ca.levelOverridden) == 1 {
+			continue
+		}
+		visited[ca] = true
+
+		if err := child.setLevel(level); err != nil {
+			return err
+		}
+		if err := child.cascadeLevel(level, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setLevel is SetLevel without marking the level as overridden
+func (l *Logger) setLevel(level int32) error {
+	if level == 0 || level&^allLevels != 0 {
+		return ErrUnknownLevel
+	}
+
+	a := l.instance()
+
+	a.mu.RLock()
+	files := a.files
+	a.mu.RUnlock()
+
+	handles := l.levelHandles(a, level, files)
+	for _, lv := range []int32{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		if err := l.SetOutput(lv, handles[lv]); err != nil {
+			return err
+		}
+	}
+
+	atomic.StoreInt32(&a.logLevel, level)
+	return nil
+}
+
+// LevelHandler returns a handler reading and changing the level, it can be
+// mounted on any http.ServeMux. GET responds with the level as JSON, e.g.
+// {"level":"info"}. PUT with a JSON body like {"level":"warn"} calls SetLevel
+// and responds with the previous and the current level. A PUT without an
+// X-Logger-Secret header matching secret gets 403 Forbidden, as does every
+// PUT when secret is empty.
+func (l *Logger) LevelHandler(secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeLevelJSON(w, struct {
+				Level string `json:"level"`
+			}{
+				Level: LevelString(l.instance().LogLevel()),
+			})
+
+		case http.MethodPut:
+			if secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Logger-Secret")), []byte(secret)) != 1 {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			var body struct {
+				Level string `json:"level"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			previous := l.instance().LogLevel()
+			level, err := ParseLevelMask(body.Level)
+			if err == nil {
+				err = l.SetLevel(level)
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			writeLevelJSON(w, struct {
+				Previous string `json:"previous"`
+				Level    string `json:"level"`
+			}{
+				Previous: LevelString(previous),
+				Level:    LevelString(l.instance().LogLevel()),
+			})
+
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// GinLevelHandler is LevelHandler for gin routes, e.g.
+// router.Any("/loglevel", l.GinLevelHandler(secret))
+func (l *Logger) GinLevelHandler(secret string) gin.HandlerFunc {
+	return gin.WrapH(l.LevelHandler(secret))
+}
+
+// writeLevelJSON writes v as the JSON response of LevelHandler
+func writeLevelJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
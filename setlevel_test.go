@@ -0,0 +1,137 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelWarn, &buf)
+
+	l.Info("hidden")
+	if err := l.SetLevel(LevelDebug); err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("shown")
+
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("unexpected output after SetLevel:\n%s", buf.String())
+	}
+	if got := l.instance().LogLevel(); got != LevelDebug {
+		t.Errorf("LogLevel = %d, want %d", got, LevelDebug)
+	}
+
+	if err := l.SetLevel(0); err != ErrUnknownLevel {
+		t.Errorf("SetLevel(0) = %v, want ErrUnknownLevel", err)
+	}
+	if err := l.SetLevel(64); err != ErrUnknownLevel {
+		t.Errorf("SetLevel(64) = %v, want ErrUnknownLevel", err)
+	}
+}
+
+func TestCascadeSetLevel(t *testing.T) {
+	root, child, grandchild, overridden := &Logger{}, &Logger{}, &Logger{}, &Logger{}
+	for _, l := range []*Logger{root, child, grandchild, overridden} {
+		l.StartWriter(LevelError, &bytes.Buffer{})
+	}
+	root.AddChild(child)
+	root.AddChild(overridden)
+	child.AddChild(grandchild)
+	// a cycle must not loop forever
+	grandchild.AddChild(root)
+
+	if err := overridden.SetLevel(LevelWarn); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.CascadeSetLevel(LevelDebug); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, l := range map[string]*Logger{"root": root, "child": child, "grandchild": grandchild} {
+		if got := l.instance().LogLevel(); got != LevelDebug {
+			t.Errorf("%s level = %d, want %d", name, got, LevelDebug)
+		}
+	}
+	if got := overridden.instance().LogLevel(); got != LevelWarn {
+		t.Errorf("overridden level = %d, want %d", got, LevelWarn)
+	}
+
+	overridden.ResetLevelOverride()
+	if err := root.CascadeSetLevel(LevelInfo); err != nil {
+		t.Fatal(err)
+	}
+	if got := overridden.instance().LogLevel(); got != LevelInfo {
+		t.Errorf("level after ResetLevelOverride = %d, want %d", got, LevelInfo)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelInfo, &bytes.Buffer{})
+	h := l.LevelHandler("s3cret")
+
+	put := func(secret, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(body))
+		if secret != "" {
+			req.Header.Set("X-Logger-Secret", secret)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, secret := range []string{"", "wrong"} {
+		if rec := put(secret, `{"level":"debug"}`); rec.Code != http.StatusForbidden {
+			t.Errorf("secret %q: status %d, want 403", secret, rec.Code)
+		}
+	}
+	if got := l.instance().LogLevel(); got != LevelInfo {
+		t.Fatalf("a forbidden PUT changed the level to %d", got)
+	}
+
+	rec := put("s3cret", `{"level":"warn"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if want := `{"previous":"info","level":"warn"}`; strings.TrimSpace(rec.Body.String()) != want {
+		t.Errorf("body %s, want %s", rec.Body.String(), want)
+	}
+	if got := l.instance().LogLevel(); got != LevelWarn {
+		t.Errorf("LogLevel = %d, want %d", got, LevelWarn)
+	}
+
+	if rec := put("s3cret", `{"level":"loud"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown level: status %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/loglevel", nil))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"warn"}` {
+		t.Errorf("GET: status %d, body %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLevelHandlerEmptySecret(t *testing.T) {
+	l := &Logger{DisableColor: true}
+	l.StartWriter(LevelInfo, &bytes.Buffer{})
+
+	req := httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(`{"level":"debug"}`))
+	req.Header.Set("X-Logger-Secret", "")
+	rec := httptest.NewRecorder()
+	l.LevelHandler("").ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("an empty secret: status %d, want 403", rec.Code)
+	}
+	if got := l.instance().LogLevel(); got != LevelInfo {
+		t.Errorf("an empty secret changed the level to %d", got)
+	}
+}
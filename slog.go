@@ -0,0 +1,97 @@
+//go:build go1.21
+// +build go1.21
+
+package applogger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler writing the records through a Logger, e.g.
+// slog.New(applogger.NewSlogHandler(l))
+type SlogHandler struct {
+	l     *Logger
+	group string
+}
+
+// NewSlogHandler returns a handler writing to l
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{l: l}
+}
+
+// slogLevel maps a slog level to the level written
+func slogLevel(level slog.Level) int32 {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+// Enabled reports whether the level is logged
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.IsLevelEnabled(slogLevel(level))
+}
+
+// Handle writes the record at its level, the attributes of the record are
+// written as fields
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	var fields []Field
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = h.appendAttr(fields, h.group, attr)
+		return true
+	})
+
+	// the caller is the function calling the slog.Logger level method
+	return h.l.write(slogLevel(r.Level), 4, r.Message+"\n", fields...)
+}
+
+// WithAttrs returns a handler writing the attributes as key=value with every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, attr := range attrs {
+		fields = h.appendAttr(fields, h.group, attr)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+
+	values := make(map[string]string, len(fields))
+	for _, f := range fields {
+		values[f.Key] = f.Value.(string)
+	}
+	return &SlogHandler{l: h.l.WithFields(values), group: h.group}
+}
+
+// WithGroup returns a handler writing the keys of the attributes as name.key
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{l: h.l, group: h.group + name + "."}
+}
+
+// appendAttr appends attr as a field, the attributes of groups are flattened to group.key
+func (h *SlogHandler) appendAttr(fields []Field, prefix string, attr slog.Attr) []Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			fields = h.appendAttr(fields, prefix, a)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + attr.Key, Value: attr.Value.String()})
+}
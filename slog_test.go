@@ -0,0 +1,82 @@
+//go:build go1.21
+// +build go1.21
+
+package applogger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandlerLevels(t *testing.T) {
+	l := &Logger{DisableColor: true}
+	l.Start(LevelDebug)
+	defer l.Stop()
+
+	buffers := make(map[int32]*bytes.Buffer)
+	for _, level := range []int32{LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		buffers[level] = &bytes.Buffer{}
+		if err := l.SetOutput(level, buffers[level]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logger := slog.New(NewSlogHandler(l))
+	logger.Debug("debug record")
+	logger.Info("info record", "user", 42)
+	logger.Warn("warn record")
+	logger.Error("error record")
+
+	for level, want := range map[int32]string{
+		LevelDebug: "DEBUG: ",
+		LevelInfo:  "INFO: ",
+		LevelWarn:  "WARNING: ",
+		LevelError: "ERROR: ",
+	} {
+		out := buffers[level].String()
+		if strings.Count(out, "\n") != 1 || !strings.HasPrefix(out, want) || !strings.Contains(out, " slog_test.go:") {
+			t.Errorf("level %d:\n%s", level, out)
+		}
+	}
+	if !strings.HasSuffix(buffers[LevelInfo].String(), ": info record user=42\n") {
+		t.Errorf("unexpected info line %q", buffers[LevelInfo].String())
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	l := &Logger{FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &bytes.Buffer{})
+
+	logger := slog.New(NewSlogHandler(l))
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Debug is enabled at LevelError")
+	}
+	if !logger.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Error is not enabled at LevelError")
+	}
+}
+
+func TestSlogHandlerAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	base := slog.New(NewSlogHandler(l))
+	logger := base.With("service", "users").WithGroup("req").With("id", "abc")
+	logger.Info("handled", "status", 200, slog.Group("db", "queries", 3))
+	base.Info("plain")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if want := ": req.id=abc service=users handled req.status=200 req.db.queries=3"; !strings.HasSuffix(lines[0], want) {
+		t.Errorf("line %q, want it to end with %q", lines[0], want)
+	}
+	if !strings.HasSuffix(lines[1], ": plain") {
+		t.Errorf("the attributes leaked into the base logger: %q", lines[1])
+	}
+}
@@ -0,0 +1,101 @@
+package applogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+)
+
+// levelCounts is the number of entries written at each level since the last summary
+type levelCounts struct {
+	debug uint64
+	info  uint64
+	warn  uint64
+	error uint64
+	total uint64
+}
+
+// startSummary resets the counters and starts the SummaryInterval goroutine
+func (l *Logger) startSummary() {
+	a := l.instance()
+
+	a.mu.Lock()
+	a.summarySince = time.Now()
+	a.mu.Unlock()
+
+	atomic.StoreUint64(&a.summaryEveryN, 0)
+	if l.SummaryEveryN > 0 {
+		atomic.StoreUint64(&a.summaryEveryN, uint64(l.SummaryEveryN))
+	}
+
+	if l.SummaryInterval <= 0 {
+		return
+	}
+
+	a.background(func(stopped <-chan struct{}) {
+		ticker := time.NewTicker(l.SummaryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopped:
+				return
+			case <-ticker.C:
+				a.writeSummary()
+			}
+		}
+	})
+}
+
+// countEntry counts an entry written at level and writes the summary
+// when SummaryEveryN entries have been written since the last one.
+func (a *ApplicationLog) countEntry(level int32) {
+	if a.levelLogger(level).Writer() == ioutil.Discard {
+		return
+	}
+
+	switch level {
+	case LevelTrace, LevelDebug:
+		atomic.AddUint64(&a.counts.debug, 1)
+	case LevelInfo:
+		atomic.AddUint64(&a.counts.info, 1)
+	case LevelWarn:
+		atomic.AddUint64(&a.counts.warn, 1)
+	default:
+		atomic.AddUint64(&a.counts.error, 1)
+	}
+
+	total := atomic.AddUint64(&a.counts.total, 1)
+	if n := atomic.LoadUint64(&a.summaryEveryN); n > 0 && total >= n {
+		a.writeSummary()
+	}
+}
+
+// writeSummary writes the number of entries per level since the last summary
+// and resets the counters. The summary line skips the transforms and is not counted.
+func (a *ApplicationLog) writeSummary() {
+	now := time.Now()
+
+	a.mu.Lock()
+	since := a.summarySince
+	a.summarySince = now
+	a.mu.Unlock()
+
+	msg := fmt.Sprintf("log summary: debug=%d info=%d warn=%d error=%d in last %s",
+		atomic.SwapUint64(&a.counts.debug, 0),
+		atomic.SwapUint64(&a.counts.info, 0),
+		atomic.SwapUint64(&a.counts.warn, 0),
+		atomic.SwapUint64(&a.counts.error, 0),
+		now.Sub(since).Round(time.Millisecond))
+	atomic.StoreUint64(&a.counts.total, 0)
+
+	entry := &LogEntry{
+		Level:     LevelInfo,
+		Timestamp: now,
+		Message:   msg,
+	}
+	if err := a.writeEntry(entry, 1); err != nil {
+		a.writeFailed(entry.Level, err)
+	}
+}
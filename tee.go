@@ -0,0 +1,73 @@
+package applogger
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// tee is a writer added with Tee
+type tee struct {
+	w io.Writer
+}
+
+// teeLevelWriter writes to the level writer and then to the tees
+type teeLevelWriter struct {
+	w io.Writer
+	a *ApplicationLog
+}
+
+// Tee sends a copy of every line written from now on to w until the returned
+// function is called, e.g. to return the recent lines from an HTTP handler.
+// Only the levels that are logged are copied. The returned function can be
+// called more than once.
+func (l *Logger) Tee(w io.Writer) func() {
+	a := l.pending()
+	t := &tee{w: w}
+
+	a.teeMu.Lock()
+	a.tees = append(append([]*tee(nil), a.tees...), t)
+	a.teeMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { a.removeTee(t) })
+	}
+}
+
+// removeTee stops copying the lines to t
+func (a *ApplicationLog) removeTee(t *tee) {
+	a.teeMu.Lock()
+	defer a.teeMu.Unlock()
+
+	tees := make([]*tee, 0, len(a.tees))
+	for _, other := range a.tees {
+		if other != t {
+			tees = append(tees, other)
+		}
+	}
+	a.tees = tees
+}
+
+// teeWriter wraps a level writer so the lines are copied to the tees,
+// levels that are not logged keep writing to ioutil.Discard
+func (a *ApplicationLog) teeWriter(w io.Writer) io.Writer {
+	if w == ioutil.Discard {
+		return w
+	}
+	return &teeLevelWriter{w: w, a: a}
+}
+
+// Write writes p to the level writer, the errors of the tees are ignored
+func (t *teeLevelWriter) Write(p []byte) (int, error) {
+	n, err := t.w.Write(p)
+
+	t.a.teeMu.Lock()
+	tees := t.a.tees
+	t.a.teeMu.Unlock()
+
+	for _, tee := range tees {
+		tee.w.Write(p)
+	}
+	return n, err
+}
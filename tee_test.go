@@ -0,0 +1,51 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTee(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	var first, second syncBuffer
+	stopFirst := l.Tee(&first)
+	stopSecond := l.Tee(&second)
+
+	l.Info("to both tees")
+	l.Debug("not logged")
+	stopFirst()
+	stopFirst()
+	l.Info("to the second tee")
+	stopSecond()
+	l.Info("to no tee")
+
+	if got := first.String(); !strings.Contains(got, "to both tees") || strings.Contains(got, "second tee") {
+		t.Errorf("first tee:\n%s", got)
+	}
+	if got := second.String(); !strings.Contains(got, "to both tees") || !strings.Contains(got, "to the second tee") ||
+		strings.Contains(got, "to no tee") || strings.Contains(got, "not logged") {
+		t.Errorf("second tee:\n%s", got)
+	}
+	if !strings.Contains(buf.String(), "to no tee") {
+		t.Errorf("the logger stopped writing:\n%s", buf.String())
+	}
+}
+
+func TestTeeBeforeStart(t *testing.T) {
+	var tee syncBuffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	defer l.Tee(&tee)()
+	l.StartWriter(LevelError, &bytes.Buffer{})
+
+	l.Info("after start")
+	if !strings.Contains(tee.String(), "after start") {
+		t.Errorf("a tee added before Start got %q", tee.String())
+	}
+}
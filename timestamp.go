@@ -0,0 +1,49 @@
+package applogger
+
+import (
+	"sync"
+	"time"
+)
+
+// timestampCache keeps the last formatted second of a layout, so the many lines
+// written within the same second share one time.Format call.
+// Only layouts without fractions of a second can be cached.
+type timestampCache struct {
+	layout string
+
+	mu     sync.RWMutex
+	second int64
+	loc    *time.Location
+	value  string
+}
+
+// caches of the layouts formatted for every line
+var (
+	apacheTimestamps = &timestampCache{layout: apacheTimeLayout}
+	w3cTimestamps    = &timestampCache{layout: "2006-01-02 15:04:05"}
+	textTimestamps   = &timestampCache{layout: "2006/01/02 15:04:05"}
+)
+
+// format returns t formatted with the layout of the cache
+func (c *timestampCache) format(t time.Time) string {
+	second := t.Unix()
+	loc := t.Location()
+
+	c.mu.RLock()
+	if c.second == second && c.loc == loc && c.value != "" {
+		value := c.value
+		c.mu.RUnlock()
+		return value
+	}
+	c.mu.RUnlock()
+
+	value := t.Format(c.layout)
+
+	c.mu.Lock()
+	c.second = second
+	c.loc = loc
+	c.value = value
+	c.mu.Unlock()
+
+	return value
+}
@@ -0,0 +1,75 @@
+package applogger
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket lets burst calls through at once and then rate calls a second
+type tokenBucket struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	dropped uint64
+}
+
+// WithTokenBucket returns a copy of the logger that writes at most burst lines
+// at once per level and then rate lines a second. Dropped lines are counted and
+// reported as "N messages suppressed" before the next line the level writes.
+func (l *Logger) WithTokenBucket(rate float64, burst int) *Logger {
+	derived := *l
+	derived.buckets = make(map[int32]*tokenBucket, 5)
+	for _, level := range []int32{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		derived.buckets[level] = &tokenBucket{
+			rate:   rate,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   time.Now(),
+		}
+	}
+	return &derived
+}
+
+// throttle reports whether a line at level may be written and writes the
+// number of lines dropped since the last one that was
+func (l *Logger) throttle(level int32, calldepth int) bool {
+	if l.buckets == nil {
+		return true
+	}
+
+	bucket, ok := l.buckets[level]
+	if !ok {
+		bucket = l.buckets[LevelError]
+	}
+
+	allowed, dropped := bucket.take()
+	if dropped > 0 {
+		msg, _ := l.redacted(fmt.Sprintf("%d messages suppressed", dropped), nil)
+		l.instance().output(level, "", calldepth+1, msg)
+	}
+	return allowed
+}
+
+// take removes a token, it returns the dropped count when the bucket recovered
+func (b *tokenBucket) take() (bool, uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		b.dropped++
+		return false, 0
+	}
+
+	b.tokens--
+	dropped := b.dropped
+	b.dropped = 0
+	return true, dropped
+}
@@ -0,0 +1,179 @@
+// Package tools contains helpers for working with applogger output after it was written.
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// maxLineSize is the longest JSONL line MergeLogs accepts
+const maxLineSize = 1024 * 1024
+
+// MergeLogs reads JSONL log streams, each sorted by time, and writes a single
+// JSONL stream sorted by time to w. Every merged entry gets a "_source" field
+// holding the file path of the reader it came from (or reader-N for readers
+// that are not files) so clock skew between sources can still be spotted.
+//
+// Entries are ordered by their "timestamp" field (RFC 3339) or the
+// instant of a FormatLog4j2JSON event. Entries without a timestamp keep
+// the time of the entry before them in the same stream. The header lines
+// of WriteFileHeader are left out.
+func MergeLogs(readers []io.Reader, w io.Writer) error {
+	h := make(mergeHeap, 0, len(readers))
+
+	for i, r := range readers {
+		src := &mergeSource{
+			index:   i,
+			name:    sourceName(r, i),
+			scanner: bufio.NewScanner(r),
+		}
+		src.scanner.Buffer(nil, maxLineSize)
+
+		entry, err := src.next()
+		if err != nil {
+			return err
+		}
+		if entry != nil {
+			h = append(h, entry)
+		}
+	}
+	heap.Init(&h)
+
+	bw := bufio.NewWriter(w)
+	for h.Len() > 0 {
+		entry := heap.Pop(&h).(*mergeEntry)
+		entry.fields["_source"] = entry.source.name
+
+		line, err := json.Marshal(entry.fields)
+		if err != nil {
+			return err
+		}
+		bw.Write(line)
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+
+		next, err := entry.source.next()
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			heap.Push(&h, next)
+		}
+	}
+	return bw.Flush()
+}
+
+// sourceName uses the file name of readers like *os.File
+func sourceName(r io.Reader, i int) string {
+	if f, ok := r.(interface{ Name() string }); ok {
+		return f.Name()
+	}
+	return fmt.Sprintf("reader-%d", i)
+}
+
+// mergeSource reads entries from one of the merged streams
+type mergeSource struct {
+	index   int
+	name    string
+	scanner *bufio.Scanner
+	line    int
+	last    time.Time
+}
+
+// next returns the next entry of the stream, or nil at the end of it
+func (s *mergeSource) next() (*mergeEntry, error) {
+	for s.scanner.Scan() {
+		s.line++
+
+		line := bytes.TrimSpace(s.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return nil, fmt.Errorf("tools: %s line %d: %s", s.name, s.line, err)
+		}
+		if fields["log_header"] == true {
+			continue
+		}
+
+		if t, ok := entryTime(fields); ok {
+			s.last = t
+		}
+		return &mergeEntry{time: s.last, source: s, line: s.line, fields: fields}, nil
+	}
+	return nil, s.scanner.Err()
+}
+
+// entryTime reads the timestamp of a JSON log entry
+func entryTime(fields map[string]interface{}) (time.Time, bool) {
+	if s, ok := fields["timestamp"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, true
+		}
+	}
+
+	if instant, ok := fields["instant"].(map[string]interface{}); ok {
+		sec, err := number(instant["epochSecond"])
+		if err != nil {
+			return time.Time{}, false
+		}
+		nsec, _ := number(instant["nanoOfSecond"])
+		return time.Unix(sec, nsec), true
+	}
+	return time.Time{}, false
+}
+
+// number converts a decoded json.Number
+func number(v interface{}) (int64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("tools: %v is not a number", v)
+	}
+	return n.Int64()
+}
+
+// mergeEntry is a decoded entry waiting in the heap
+type mergeEntry struct {
+	time   time.Time
+	source *mergeSource
+	line   int
+	fields map[string]interface{}
+}
+
+// mergeHeap is a min-heap of entries ordered by time. Entries with the
+// same time keep the order of the readers and of the lines.
+type mergeHeap []*mergeEntry
+
+func (h mergeHeap) Len() int { return len(h) }
+
+func (h mergeHeap) Less(i, j int) bool {
+	if !h[i].time.Equal(h[j].time) {
+		return h[i].time.Before(h[j].time)
+	}
+	if h[i].source.index != h[j].source.index {
+		return h[i].source.index < h[j].source.index
+	}
+	return h[i].line < h[j].line
+}
+
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(*mergeEntry)) }
+
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
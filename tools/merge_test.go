@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMergeLogs(t *testing.T) {
+	a := strings.NewReader(`{"log_header":true,"start_time":"2024-01-15T10:00:00Z","format":"json"}
+{"timestamp":"2024-01-15T10:00:01Z","message":"a1"}
+{"timestamp":"2024-01-15T10:00:03Z","message":"a2"}
+`)
+	b := strings.NewReader(`{"timestamp":"2024-01-15T10:00:02Z","message":"b1"}
+
+{"message":"b2"}
+{"timestamp":"2024-01-15T10:00:04Z","message":"b3"}
+`)
+
+	var out bytes.Buffer
+	if err := MergeLogs([]io.Reader{a, b}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages, sources []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid line %q: %s", line, err)
+		}
+		messages = append(messages, entry["message"].(string))
+		sources = append(sources, entry["_source"].(string))
+	}
+
+	if got := strings.Join(messages, ","); got != "a1,b1,b2,a2,b3" {
+		t.Errorf("merged order %s", got)
+	}
+	if got := strings.Join(sources, ","); got != "reader-0,reader-1,reader-1,reader-0,reader-1" {
+		t.Errorf("sources %s", got)
+	}
+}
+
+func TestMergeLogsInvalidLine(t *testing.T) {
+	err := MergeLogs([]io.Reader{strings.NewReader("{\"message\":\"ok\"}\nnot json\n")}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "reader-0 line 2") {
+		t.Errorf("MergeLogs error = %v", err)
+	}
+}
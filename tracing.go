@@ -0,0 +1,161 @@
+package applogger
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ginSpanKey is the gin context key of the span started by GinTracingMiddleware
+const ginSpanKey = "applogger.span"
+
+// spanContextKey is the context key of the span started by GinTracingMiddleware
+type spanContextKey struct{}
+
+// SpanContext identifies a span of a W3C trace context, see
+// https://www.w3.org/TR/trace-context/
+type SpanContext struct {
+	// TraceID is the 32 hex digit id shared by every span of the trace
+	TraceID string
+	// SpanID is the 16 hex digit id of the span
+	SpanID string
+	// ParentSpanID is the SpanID of the caller, empty for the first span of a trace
+	ParentSpanID string
+	// Sampled is the sampled flag of the trace
+	Sampled bool
+}
+
+// TraceParent formats the span as a traceparent header, e.g. to pass the
+// trace on to the services called while handling the request
+func (s SpanContext) TraceParent() string {
+	flags := "00"
+	if s.Sampled {
+		flags = "01"
+	}
+	return "00-" + s.TraceID + "-" + s.SpanID + "-" + flags
+}
+
+// ParseTraceParent reads a traceparent header, it reports false when the
+// header is not a valid version 00 traceparent
+func ParseTraceParent(header string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || parts[0] == "ff" || !isHex(parts[0], 2) {
+		return SpanContext{}, false
+	}
+	// version 00 has exactly four parts, later versions may add more
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	traceID, spanID, flags := parts[1], parts[2], parts[3]
+	if !isHex(traceID, 32) || !isHex(spanID, 16) || !isHex(flags, 2) {
+		return SpanContext{}, false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return SpanContext{}, false
+	}
+
+	var sampled [1]byte
+	hex.Decode(sampled[:], []byte(flags))
+	return SpanContext{TraceID: traceID, SpanID: spanID, Sampled: sampled[0]&1 == 1}, true
+}
+
+// isHex reports whether s is n lower case hex digits
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// SpanFromContext returns the span GinTracingMiddleware started for the request,
+// from the request context or the gin context
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	if c, ok := ctx.(*gin.Context); ok {
+		if v, ok := c.Get(ginSpanKey); ok {
+			span, ok := v.(SpanContext)
+			return span, ok
+		}
+		if c.Request == nil {
+			return SpanContext{}, false
+		}
+		ctx = c.Request.Context()
+	}
+
+	span, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return span, ok
+}
+
+// childSpan starts a span under the traceparent header, or a new trace when
+// the header is missing or invalid
+func childSpan(traceParent string) SpanContext {
+	if parent, ok := ParseTraceParent(traceParent); ok {
+		return SpanContext{
+			TraceID:      parent.TraceID,
+			SpanID:       randomHex(8),
+			ParentSpanID: parent.SpanID,
+			Sampled:      parent.Sampled,
+		}
+	}
+	return SpanContext{TraceID: randomHex(16), SpanID: randomHex(8), Sampled: true}
+}
+
+// randomHex returns n random bytes as hex digits
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := crand.Read(b); err != nil {
+		// the ids only have to be unique, not secret
+		for i := range b {
+			b[i] = byte(sampleFloat() * 256)
+		}
+	}
+	return hex.EncodeToString(b)
+}
+
+// GinTracingMiddleware starts a child span of the traceparent header of every
+// request, or a new trace without one, and ends it once the request is handled.
+// The span is kept in the gin context and the request context, see
+// SpanFromContext, and its ids are written by the Ctx methods. The end of the
+// span is written at Debug level with the route, method, status and duration.
+// Use it before GinLogger so tracing and logging share the request.
+func (l *Logger) GinTracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span := childSpan(c.Request.Header.Get("traceparent"))
+
+		ctx := context.WithValue(c.Request.Context(), spanContextKey{}, span)
+		ctx = context.WithValue(ctx, ContextKeyTraceID, span.TraceID)
+		ctx = context.WithValue(ctx, ContextKeySpanID, span.SpanID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(ginSpanKey, span)
+
+		t := time.Now()
+		defer func() {
+			if nologDebug {
+				return
+			}
+			name := fmt.Sprintf("%s %s", c.Request.Method, routePattern(c))
+			l.write(LevelDebug, 1, "[GIN] span "+name,
+				Field{Key: "trace", Value: span.TraceID},
+				Field{Key: "span", Value: span.SpanID},
+				Field{Key: "parent_span", Value: span.ParentSpanID},
+				Field{Key: "status", Value: c.Writer.Status()},
+				Field{Key: "duration", Value: time.Since(t)},
+			)
+		}()
+
+		c.Next()
+	}
+}
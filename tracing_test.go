@@ -0,0 +1,121 @@
+//go:build !nolog_debug && !nolog_all
+// +build !nolog_debug,!nolog_all
+
+package applogger
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		header string
+		ok     bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		span, ok := ParseTraceParent(tt.header)
+		if ok != tt.ok {
+			t.Errorf("ParseTraceParent(%q) ok = %v, want %v", tt.header, ok, tt.ok)
+			continue
+		}
+		if ok && span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("ParseTraceParent(%q) trace = %s", tt.header, span.TraceID)
+		}
+	}
+
+	span, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if !span.Sampled || span.TraceParent() != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("round trip of %#v gave %s", span, span.TraceParent())
+	}
+}
+
+func TestGinTracingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir, err := ioutil.TempDir("", "applogger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := &Logger{DisableColor: true}
+	l.StartFile(LevelDebug, dir, 1)
+	defer l.Stop()
+
+	var fromGin, fromRequest SpanContext
+	r := gin.New()
+	r.Use(l.GinTracingMiddleware())
+	r.GET("/users/:id", func(c *gin.Context) {
+		fromGin, _ = SpanFromContext(c)
+		fromRequest, _ = SpanFromContext(c.Request.Context())
+		l.InfoCtx(c.Request.Context(), "GetUser : Completed")
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if fromGin != fromRequest {
+		t.Errorf("gin context span %#v, request context span %#v", fromGin, fromRequest)
+	}
+	if fromGin.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || fromGin.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("span is not a child of the traceparent: %#v", fromGin)
+	}
+	if !isHex(fromGin.SpanID, 16) || fromGin.SpanID == fromGin.ParentSpanID {
+		t.Errorf("span id %q", fromGin.SpanID)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "*.txt"))
+	if len(files) != 1 {
+		t.Fatalf("log files %v", files)
+	}
+	out, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "[trace=4bf92f3577b34da6a3ce929d0e0e4736 span="+fromGin.SpanID+"] GetUser : Completed") {
+		t.Errorf("the Ctx line misses the span ids:\n%s", out)
+	}
+	if !strings.Contains(string(out), "[GIN] span GET /users/:id trace=4bf92f3577b34da6a3ce929d0e0e4736 span="+fromGin.SpanID+" parent_span=00f067aa0ba902b7 status=204") {
+		t.Errorf("the end of the span is not logged:\n%s", out)
+	}
+}
+
+func TestGinTracingMiddlewareStartsTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	l := &Logger{}
+	l.Start(LevelError)
+
+	var span SpanContext
+	r := gin.New()
+	r.Use(l.GinTracingMiddleware())
+	r.GET("/", func(c *gin.Context) {
+		span, _ = SpanFromContext(c)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !isHex(span.TraceID, 32) || !isHex(span.SpanID, 16) || span.ParentSpanID != "" || !span.Sampled {
+		t.Errorf("unexpected new span %#v", span)
+	}
+}
@@ -0,0 +1,167 @@
+package applogger
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+)
+
+// redacted replaces any value removed by RedactTransform
+const redacted = "[REDACTED]"
+
+// Transform changes an entry before it is formatted and written.
+// Returning nil drops the entry.
+type Transform func(entry *LogEntry) *LogEntry
+
+// AddTransform registers a transform. Transforms run in the order they were added.
+func (l *Logger) AddTransform(t Transform) {
+	a := l.pending()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.transforms = append(a.transforms, t)
+}
+
+// RedactTransform replaces every match of the patterns in the message and
+// in string field values with [REDACTED]. Only the first group of a pattern
+// with groups is replaced, e.g. password=(\S+) writes password=[REDACTED].
+func RedactTransform(patterns []*regexp.Regexp) Transform {
+	return func(entry *LogEntry) *LogEntry {
+		e := *entry
+		e.Message = redact(e.Message, patterns)
+		e.Fields = redactFields(entry.Fields, patterns)
+		return &e
+	}
+}
+
+// redact replaces every match of the patterns in s with [REDACTED].
+// Patterns are skipped unless s contains the literal they require, the
+// regexp engine costs far more than the search.
+func redact(s string, patterns []*regexp.Regexp) string {
+	for _, p := range patterns {
+		if literal := requiredLiteral(p); literal != "" && !strings.Contains(s, literal) {
+			continue
+		}
+		s = redactMatches(s, p)
+	}
+	return s
+}
+
+// redactMatches replaces the matches of p in s, or their first group if p has groups
+func redactMatches(s string, p *regexp.Regexp) string {
+	if p.NumSubexp() == 0 {
+		return p.ReplaceAllString(s, redacted)
+	}
+
+	matches := p.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(redacted)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// requiredLiterals caches the literal required by each pattern, by pattern
+var requiredLiterals sync.Map
+
+// requiredLiteral returns the longest literal every match of p contains, e.g.
+// @ for an email address, or "" if there is none
+func requiredLiteral(p *regexp.Regexp) string {
+	if literal, ok := requiredLiterals.Load(p); ok {
+		return literal.(string)
+	}
+
+	var literal string
+	if re, err := syntax.Parse(p.String(), syntax.Perl); err == nil {
+		literal = syntaxLiteral(re.Simplify())
+	}
+	requiredLiterals.Store(p, literal)
+	return literal
+}
+
+// syntaxLiteral returns the longest literal every match of re contains
+func syntaxLiteral(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return ""
+		}
+		return string(re.Rune)
+	case syntax.OpCapture, syntax.OpPlus:
+		return syntaxLiteral(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return syntaxLiteral(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		var longest string
+		for _, sub := range re.Sub {
+			if literal := syntaxLiteral(sub); len(literal) > len(longest) {
+				longest = literal
+			}
+		}
+		return longest
+	}
+	return ""
+}
+
+// redactFields returns a copy of fields with the patterns redacted from the string values
+func redactFields(fields []Field, patterns []*regexp.Regexp) []Field {
+	if fields == nil {
+		return nil
+	}
+
+	redactedFields := make([]Field, len(fields))
+	for i, f := range fields {
+		if s, ok := f.Value.(string); ok {
+			f.Value = redact(s, patterns)
+		}
+		redactedFields[i] = f
+	}
+	return redactedFields
+}
+
+// AddRedactPattern compiles pattern and adds it to RedactPatterns, call it
+// before logging starts
+func (l *Logger) AddRedactPattern(pattern string) error {
+	p, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	l.RedactPatterns = append(l.RedactPatterns, p)
+	return nil
+}
+
+// EnrichTransform appends fields to every entry
+func EnrichTransform(fields []Field) Transform {
+	return func(entry *LogEntry) *LogEntry {
+		e := *entry
+		e.Fields = make([]Field, 0, len(entry.Fields)+len(fields))
+		e.Fields = append(e.Fields, entry.Fields...)
+		e.Fields = append(e.Fields, fields...)
+		return &e
+	}
+}
+
+// FilterTransform drops every entry the predicate returns false for
+func FilterTransform(predicate func(*LogEntry) bool) Transform {
+	return func(entry *LogEntry) *LogEntry {
+		if !predicate(entry) {
+			return nil
+		}
+		return entry
+	}
+}
@@ -0,0 +1,156 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// benchRedactPatterns are five typical patterns of tokens, passwords and PII
+var benchRedactPatterns = []string{
+	`password=(\S+)`,
+	`token=([A-Za-z0-9._-]+)`,
+	`Bearer [A-Za-z0-9._-]+`,
+	`\b\d{4}-\d{4}-\d{4}-\d{4}\b`,
+	`[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`,
+}
+
+func TestRedactPatterns(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	for _, p := range benchRedactPatterns {
+		if err := l.AddRedactPattern(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.StartWriter(LevelError, &buf)
+
+	l.Error("Login : Failed for user=bob password=secret")
+	l.Error("Login : Failed with Authorization Bearer abc.def and card 4111-1111-1111-1111")
+
+	want := []string{
+		"Login : Failed for user=bob password=[REDACTED]",
+		"Login : Failed with Authorization [REDACTED] and card [REDACTED]",
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, want[i]) {
+			t.Errorf("line %d = %q, want suffix %q", i, line, want[i])
+		}
+	}
+}
+
+func TestRedactFatalPanic(t *testing.T) {
+	var buf bytes.Buffer
+	var exits, panics int
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.ExitFunc = func(int) { exits++ }
+	l.PanicFunc = func(interface{}) { panics++ }
+	if err := l.AddRedactPattern(`password=(\S+)`); err != nil {
+		t.Fatal(err)
+	}
+	a := l.StartWriter(LevelError, &buf)
+
+	l.Fatal("Login : password=secret1")
+	l.Panic("Login : password=secret2")
+	a.Fatal("Login : password=secret3")
+	a.Panic("Login : password=secret4")
+	Error("Login : password=secret5")
+
+	if exits != 2 || panics != 2 {
+		t.Errorf("%d exits and %d panics, want 2 and 2", exits, panics)
+	}
+	out := buf.String()
+	if strings.Contains(out, "secret") || strings.Count(out, "password=[REDACTED]") != 5 {
+		t.Errorf("a password was written:\n%s", out)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, in, want string
+	}{
+		{`password=(\S+)`, "password=secret", "password=[REDACTED]"},
+		{`password=(\S+)`, "a password=x b password=y", "a password=[REDACTED] b password=[REDACTED]"},
+		{`secret`, "password=secret", "password=[REDACTED]"},
+		{`(?i)token=\S+`, "TOKEN=abc", "[REDACTED]"},
+		{`[a-z]+@[a-z]+\.com`, "mail alice@example.com", "mail [REDACTED]"},
+		{`[a-z]+@[a-z]+\.com`, "nothing to hide", "nothing to hide"},
+	} {
+		if got := redact(tt.in, []*regexp.Regexp{regexp.MustCompile(tt.pattern)}); got != tt.want {
+			t.Errorf("redact(%q) with %s = %q, want %q", tt.in, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestRequiredLiteral(t *testing.T) {
+	for pattern, want := range map[string]string{
+		`password=(\S+)`:                        "password=",
+		`[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`: "@",
+		`\b\d{4}-\d{4}-\d{4}-\d{4}\b`:           "-",
+		`(?i)token=\S+`:                         "",
+		`(?:user|login)=\S+`:                    "=",
+		`x*y?`:                                  "",
+	} {
+		if got := requiredLiteral(regexp.MustCompile(pattern)); got != want {
+			t.Errorf("requiredLiteral(%s) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestRedactFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatJSON, DisableColor: true, FileLogLevel: LevelInfo}
+	if err := l.AddRedactPattern(`\d{3}-\d{2}-\d{4}`); err != nil {
+		t.Fatal(err)
+	}
+	l.StartWriter(LevelError, &buf)
+
+	l.ErrorFields("Signup : Failed", StringField("ssn", "123-45-6789"), IntField("attempts", 3))
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("invalid JSON %q: %s", buf.String(), err)
+	}
+	if line["ssn"] != redacted || line["attempts"] != float64(3) {
+		t.Errorf("fields = %v", line)
+	}
+}
+
+func TestAddRedactPatternInvalid(t *testing.T) {
+	l := &Logger{}
+	if err := l.AddRedactPattern(`(`); err == nil {
+		t.Error("an invalid pattern was accepted")
+	}
+	if len(l.RedactPatterns) != 0 {
+		t.Errorf("the invalid pattern was added: %v", l.RedactPatterns)
+	}
+}
+
+func benchmarkRedact(b *testing.B, line string) {
+	patterns := make([]*regexp.Regexp, len(benchRedactPatterns))
+	for i, p := range benchRedactPatterns {
+		patterns[i] = regexp.MustCompile(p)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		redact(line, patterns)
+	}
+}
+
+func BenchmarkRedactClean(b *testing.B) {
+	benchmarkRedact(b, "GetUser : Completed in 12ms for id=42")
+}
+
+func BenchmarkRedactMatch(b *testing.B) {
+	benchmarkRedact(b, "Login : Failed for user=bob password=secret")
+}
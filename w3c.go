@@ -0,0 +1,38 @@
+package applogger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// w3cFields are the fields written for every request, in order
+const w3cFields = "date time cs-method cs-uri-stem sc-status time-taken"
+
+// w3cHeader returns the directives written once when logging is turned on.
+// W3C timestamps are always UTC.
+func w3cHeader(t time.Time) string {
+	return fmt.Sprintf("#Version: 1.0\n#Date: %s\n#Fields: %s\n", t.UTC().Format("2006-01-02 15:04:05"), w3cFields)
+}
+
+// w3cExtended formats the request as a W3C Extended Log Format line.
+// time-taken is in milliseconds as written by IIS.
+func w3cExtended(e GinLogEntry) string {
+	return strings.Join([]string{
+		w3cTimestamps.format(e.start.UTC()),
+		w3cField(e.Method),
+		w3cField(e.Path),
+		strconv.Itoa(e.Status),
+		strconv.FormatInt(int64(e.Latency/time.Millisecond), 10),
+	}, " ")
+}
+
+// w3cField replaces empty values with "-" and spaces with "+"
+// so every value stays a single space separated field
+func w3cField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.Replace(s, " ", "+", -1)
+}
@@ -0,0 +1,71 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestW3CHeader(t *testing.T) {
+	at := time.Date(2006, 1, 2, 15, 4, 5, 0, time.FixedZone("", -7*3600))
+	want := "#Version: 1.0\n#Date: 2006-01-02 22:04:05\n#Fields: date time cs-method cs-uri-stem sc-status time-taken\n"
+	if got := w3cHeader(at); got != want {
+		t.Errorf("w3cHeader = %q, want %q", got, want)
+	}
+}
+
+func TestW3CHeaderInFile(t *testing.T) {
+	dir := tempDir(t)
+	l := &Logger{Format: FormatW3CExtended}
+	l.StartFile(LevelError, dir, 1)
+	defer l.Stop()
+
+	lines := strings.Split(readLogFile(t, dir), "\n")
+	if len(lines) < 3 || lines[0] != "#Version: 1.0" || !strings.HasPrefix(lines[1], "#Date: ") || lines[2] != "#Fields: "+w3cFields {
+		t.Errorf("the file does not start with the directives:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestW3CExtendedEscaping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatW3CExtended}
+	l.StartWriter(LevelInfo, &bytes.Buffer{})
+
+	r := gin.New()
+	r.Use(l.GinLoggerWithConfig(GinLoggerConfig{Output: &buf}))
+	r.Handle("PURGE", "/cache/:name", func(c *gin.Context) { c.Status(http.StatusAccepted) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PURGE", "/cache/my%20file", nil))
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	if !regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2} PURGE /cache/my\+file 202 \d+$`).MatchString(line) {
+		t.Errorf("unexpected W3C line %q", line)
+	}
+	if n := len(strings.Fields(line)); n != len(strings.Fields(w3cFields)) {
+		t.Errorf("%d fields, want one per #Fields entry: %q", n, line)
+	}
+}
+
+func TestW3CField(t *testing.T) {
+	for in, want := range map[string]string{
+		"":       "-",
+		"/a b c": "/a+b+c",
+		"/plain": "/plain",
+		"  ":     "++",
+	} {
+		if got := w3cField(in); got != want {
+			t.Errorf("w3cField(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package applogger
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithField returns a copy of the logger writing key=value with every line,
+// l keeps its own fields
+func (l *Logger) WithField(key, value string) *Logger {
+	return l.WithFields(map[string]string{key: value})
+}
+
+// WithFields returns a copy of the logger writing the fields with every line,
+// l keeps its own fields. The fields come first, sorted by key: text lines
+// start with them, e.g. key=value key2=value2 message, and the structured
+// formats write them before the fields of the call.
+func (l *Logger) WithFields(fields map[string]string) *Logger {
+	derived := *l
+	derived.fields = make(map[string]string, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		derived.fields[k] = v
+	}
+	for k, v := range fields {
+		derived.fields[k] = v
+	}
+	return &derived
+}
+
+// withFields prepends the fields of WithFields to the fields of the call
+func (l *Logger) withFields(fields []Field) []Field {
+	if len(l.fields) == 0 {
+		return fields
+	}
+
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	with := make([]Field, 0, len(keys)+len(fields))
+	for _, k := range keys {
+		with = append(with, Field{Key: k, Value: l.fields[k]})
+	}
+	return append(with, fields...)
+}
+
+// withFieldsMessage prepends the fields of WithFields to msg for the text formats
+func (l *Logger) withFieldsMessage(msg string) string {
+	if len(l.fields) == 0 {
+		return msg
+	}
+	return strings.TrimPrefix(textFields(l.withFields(nil)), " ") + " " + msg
+}
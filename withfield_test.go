@@ -0,0 +1,81 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithFieldsNotShared(t *testing.T) {
+	var buf bytes.Buffer
+	base := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	base.StartWriter(LevelError, &buf)
+
+	users := base.WithField("service", "users")
+	orders := base.WithFields(map[string]string{"service": "orders", "region": "eu"})
+	admin := users.WithField("role", "admin")
+
+	base.Info("base")
+	users.Info("users")
+	orders.Info("orders")
+	admin.Info("admin")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("%d lines, want 4:\n%s", len(lines), buf.String())
+	}
+	for i, want := range []string{
+		": base",
+		": service=users users",
+		": region=eu service=orders orders",
+		": role=admin service=users admin",
+	} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("line %d = %q, want it to end with %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestWithFieldsText(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	l.WithFields(map[string]string{"req": "abc123", "trace": "def456"}).InfoFields("login", Field{Key: "method", Value: "otp"})
+
+	if !strings.HasSuffix(buf.String(), ": req=abc123 trace=def456 login method=otp\n") {
+		t.Errorf("unexpected line %s", buf.String())
+	}
+}
+
+func TestWithFieldsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{Format: FormatJSON, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	l.WithField("user", "42").InfoFields("login", Field{Key: "method", Value: "otp"})
+
+	if !strings.Contains(buf.String(), `"message":"login","user":"42","method":"otp"}`) {
+		t.Errorf("unexpected line %s", buf.String())
+	}
+}
+
+func TestWithFieldsRequired(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	required := l.RequireFields("request_id")
+	required.WithField("request_id", "abc").Info("with the id")
+	if strings.Contains(buf.String(), "missing required log field") {
+		t.Errorf("a field of WithField was reported missing:\n%s", buf.String())
+	}
+
+	required.Info("without the id")
+	if !strings.Contains(buf.String(), "WARNING: ") || !strings.Contains(buf.String(), "missing required log field: request_id\n") {
+		t.Errorf("the missing field was not reported:\n%s", buf.String())
+	}
+}
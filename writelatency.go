@@ -0,0 +1,64 @@
+package applogger
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+)
+
+// latencyWriter measures the writes of a level to one destination
+type latencyWriter struct {
+	w          io.Writer
+	level      string
+	writerType string
+	m          *metricVec
+}
+
+// latencyWriter wraps the writer of a level so the duration of every write is
+// observed in the write latency histogram, levels that are not logged keep
+// writing to ioutil.Discard
+func (a *ApplicationLog) latencyWriter(level int32, writerType string, w io.Writer) io.Writer {
+	if w == ioutil.Discard {
+		return w
+	}
+	return &latencyWriter{w: w, level: LevelString(level), writerType: writerType, m: a.writeLatency()}
+}
+
+// Write writes p and observes how long it took
+func (lw *latencyWriter) Write(p []byte) (int, error) {
+	t := time.Now()
+	n, err := lw.w.Write(p)
+	lw.m.observe(time.Since(t), lw.level, lw.writerType)
+	return n, err
+}
+
+// writerType is the writer_type label of a file writer: file for the log
+// files, writer for the writers of StartWriter and SetFileWriter
+func writerType(w io.Writer) string {
+	switch w.(type) {
+	case *lockedFile, *os.File:
+		return "file"
+	}
+	return "writer"
+}
+
+// writeLatency returns the write latency histogram, created on first use
+func (a *ApplicationLog) writeLatency() *metricVec {
+	a.latencyOnce.Do(func() {
+		a.latency = newHistogramVec("applogger_write_duration_seconds", "Duration of the log writes.", "level", "writer_type")
+	})
+	return a.latency
+}
+
+// WriteLatencyHandler serves the histogram applogger_write_duration_seconds
+// of the log writes by level and writer_type (console, file or writer) in the
+// OpenMetrics text format, for Prometheus to scrape, e.g. on /metrics/log.
+// Slow writes show a remote writer or a full disk holding up the logging.
+func (l *Logger) WriteLatencyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", openMetricsContentType)
+		writeOpenMetrics(w, l.instance().writeLatency())
+	})
+}
@@ -0,0 +1,64 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteLatencyHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	l.StartWriter(LevelError, &buf)
+
+	l.Info("first")
+	l.Info("second")
+	l.Debug("not logged")
+
+	rec := httptest.NewRecorder()
+	l.WriteLatencyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/log", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != openMetricsContentType {
+		t.Errorf("Content-Type %q", ct)
+	}
+
+	out := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE applogger_write_duration_seconds histogram",
+		`applogger_write_duration_seconds_count{level="info",writer_type="writer"} 2`,
+		`applogger_write_duration_seconds_bucket{level="info",writer_type="writer",le="+Inf"} 2`,
+		"# EOF",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("%s missing in:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `level="debug"`) || strings.Contains(out, `writer_type="console"`) {
+		t.Errorf("writes that were not made were counted:\n%s", out)
+	}
+}
+
+func TestWriteLatencyFile(t *testing.T) {
+	l := &Logger{DisableColor: true, FileLogLevel: LevelWarn}
+	l.StartFile(LevelError, tempDir(t), 1)
+	defer l.Stop()
+
+	l.Warning("to the file")
+	l.Error("to the file and the console")
+
+	var buf bytes.Buffer
+	writeOpenMetrics(&buf, l.instance().writeLatency())
+	for _, line := range []string{
+		`applogger_write_duration_seconds_count{level="warn",writer_type="file"} 1`,
+		`applogger_write_duration_seconds_count{level="error",writer_type="file"} 1`,
+		`applogger_write_duration_seconds_count{level="error",writer_type="console"} 1`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("%s missing in:\n%s", line, buf.String())
+		}
+	}
+}
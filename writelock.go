@@ -0,0 +1,36 @@
+package applogger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Lock blocks the log writes of every other goroutine until the returned
+// function is called, so the lines written in between stay together.
+// It is a last resort for diagnostic sections: keep the section short, never
+// call Lock again before unlocking and always unlock, e.g. with defer, or
+// every goroutine that logs deadlocks.
+func (l *Logger) Lock() func() {
+	a := l.instance()
+	a.writeMu.Lock()
+	atomic.StoreUint64(&a.writeOwner, goroutineID())
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.StoreUint64(&a.writeOwner, 0)
+			a.writeMu.Unlock()
+		})
+	}
+}
+
+// lockWrite waits for a Lock held by another goroutine, the returned function
+// releases the write
+func (a *ApplicationLog) lockWrite() func() {
+	if owner := atomic.LoadUint64(&a.writeOwner); owner != 0 && owner == goroutineID() {
+		return func() {}
+	}
+
+	a.writeMu.Lock()
+	return a.writeMu.Unlock
+}
@@ -0,0 +1,77 @@
+//go:build !nolog_all
+// +build !nolog_all
+
+package applogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartWriter(t *testing.T) {
+	var plain, colored bytes.Buffer
+	plainLogger := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	plainLogger.StartWriter(LevelError, &plain)
+	coloredLogger := &Logger{FileLogLevel: LevelInfo}
+	coloredLogger.StartWriter(LevelError, &colored)
+
+	plainLogger.Info("to the buffer")
+	coloredLogger.Info("to the buffer")
+
+	if !strings.HasPrefix(plain.String(), "INFO: ") || !strings.Contains(plain.String(), "writer_test.go:") ||
+		!strings.HasSuffix(plain.String(), ": to the buffer\n") {
+		t.Errorf("plain line %q", plain.String())
+	}
+	if !strings.HasPrefix(colored.String(), "\x1b[") || !strings.Contains(colored.String(), "INFO: ") {
+		t.Errorf("colored line %q", colored.String())
+	}
+}
+
+func TestStartWriterUTC(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	defer func() { time.Local = local }()
+
+	var localBuf, utcBuf bytes.Buffer
+	localLogger := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	localLogger.StartWriter(LevelError, &localBuf)
+	utcLogger := &Logger{DisableColor: true, DataTimeUTC: true, FileLogLevel: LevelInfo}
+	utcLogger.StartWriter(LevelError, &utcBuf)
+
+	now := time.Now()
+	localLogger.Info("local")
+	utcLogger.Info("utc")
+
+	// The hours differ by 5, also when the minute changes in between
+	hour := func(buf *bytes.Buffer) string {
+		fields := strings.Fields(buf.String())
+		if len(fields) < 3 {
+			t.Fatalf("unexpected line %q", buf.String())
+		}
+		return fields[2][:2]
+	}
+	if got, want := hour(&localBuf), now.Format("15"); got != want {
+		t.Errorf("local hour %s, want %s", got, want)
+	}
+	if got, want := hour(&utcBuf), now.UTC().Format("15"); got != want {
+		t.Errorf("UTC hour %s, want %s", got, want)
+	}
+}
+
+func TestStartFileAndWriter(t *testing.T) {
+	var buf syncBuffer
+	l := &Logger{DisableColor: true, FileLogLevel: LevelInfo}
+	a := l.StartFileAndWriter(LevelError, tempDir(t), 1, &buf)
+	defer l.Stop()
+
+	l.Info("to both")
+
+	if !strings.Contains(readFile(t, a.LogFile.Name()), ": to both\n") {
+		t.Error("the line is missing in the file")
+	}
+	if !strings.HasPrefix(buf.String(), "INFO: ") || !strings.HasSuffix(buf.String(), ": to both\n") {
+		t.Errorf("extra writer %q", buf.String())
+	}
+}
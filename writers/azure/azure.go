@@ -0,0 +1,298 @@
+// Package azure uploads log lines to Azure Blob Storage as append blobs.
+//
+// The writer talks to the Blob service REST API directly and authenticates
+// with a shared access signature, so no Azure SDK is needed.
+package azure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codingmechanics/applogger"
+)
+
+const (
+	// DefaultFlushSize is the buffered size of a blob that triggers an upload
+	DefaultFlushSize = 1024 * 1024
+
+	// DefaultFlushInterval is how often buffered lines are uploaded
+	DefaultFlushInterval = 10 * time.Second
+
+	// maxAppendBlock is the largest block accepted by Append Block
+	maxAppendBlock = 4 * 1024 * 1024
+
+	// apiVersion is the Blob service version the requests are written for
+	apiVersion = "2019-12-12"
+)
+
+// ErrClosed is returned when writing to a closed AzureBlobWriter
+var ErrClosed = errors.New("azure: writer is closed")
+
+// AzureBlobWriter buffers log lines and appends them to one blob per day and
+// level, named like 2006-01-02-info.log. Lines written through Write without a
+// level go to 2006-01-02.log. A FlushInterval of 0 only uploads full buffers
+// and the lines left on Close. FlushSize and FlushInterval can be changed until
+// the first Write.
+type AzureBlobWriter struct {
+	FlushSize     int
+	FlushInterval time.Duration
+
+	containerURL string
+	sasToken     string
+	client       *http.Client
+
+	mu      sync.Mutex
+	buffers map[string]*bytes.Buffer
+	created map[string]bool
+	closed  bool
+
+	sendMu    sync.Mutex
+	start     sync.Once
+	closeOnce sync.Once
+	flush     chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewAzureBlobWriter creates a writer for the container, e.g.
+// https://account.blob.core.windows.net/logs, using a SAS token with
+// create and write permissions.
+func NewAzureBlobWriter(containerURL, sasToken string) (*AzureBlobWriter, error) {
+	u, err := url.Parse(containerURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("azure: unsupported container URL scheme %q", u.Scheme)
+	}
+	if sasToken == "" {
+		return nil, errors.New("azure: SAS token is required")
+	}
+
+	return &AzureBlobWriter{
+		FlushSize:     DefaultFlushSize,
+		FlushInterval: DefaultFlushInterval,
+		containerURL:  strings.TrimRight(containerURL, "/"),
+		sasToken:      strings.TrimPrefix(sasToken, "?"),
+		client:        &http.Client{Timeout: 30 * time.Second},
+		buffers:       make(map[string]*bytes.Buffer),
+		created:       make(map[string]bool),
+		flush:         make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// Write buffers p for the blob of the day
+func (w *AzureBlobWriter) Write(p []byte) (int, error) {
+	return w.write("", p)
+}
+
+// Level returns a writer that buffers lines for the blob of the day and level
+func (w *AzureBlobWriter) Level(level int32) io.Writer {
+	return levelWriter{w: w, level: levelName(level)}
+}
+
+// levelWriter writes to an AzureBlobWriter with a fixed level
+type levelWriter struct {
+	w     *AzureBlobWriter
+	level string
+}
+
+func (lw levelWriter) Write(p []byte) (int, error) {
+	return lw.w.write(lw.level, p)
+}
+
+// levelName is the level part of the blob name
+func levelName(level int32) string {
+	switch level {
+	case applogger.LevelTrace:
+		return "trace"
+	case applogger.LevelDebug:
+		return "debug"
+	case applogger.LevelInfo:
+		return "info"
+	case applogger.LevelWarn:
+		return "warning"
+	case applogger.LevelFatal:
+		return "fatal"
+	default:
+		return "error"
+	}
+}
+
+// blobName names the blob for the day and level
+func blobName(t time.Time, level string) string {
+	if level == "" {
+		return t.UTC().Format("2006-01-02") + ".log"
+	}
+	return t.UTC().Format("2006-01-02") + "-" + level + ".log"
+}
+
+// write buffers p and wakes the uploader once the blob buffer is full
+func (w *AzureBlobWriter) write(level string, p []byte) (int, error) {
+	w.start.Do(w.run)
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrClosed
+	}
+	name := blobName(time.Now(), level)
+	buf, ok := w.buffers[name]
+	if !ok {
+		buf = new(bytes.Buffer)
+		w.buffers[name] = buf
+	}
+	buf.Write(p)
+	full := buf.Len() >= w.FlushSize
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.flush <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// run starts the goroutine uploading the buffered lines
+func (w *AzureBlobWriter) run() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		var tick <-chan time.Time
+		if w.FlushInterval > 0 {
+			ticker := time.NewTicker(w.FlushInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-tick:
+			case <-w.flush:
+			}
+
+			if err := w.Flush(); err != nil {
+				fmt.Fprintf(os.Stderr, "azure: upload failed: %s\n", err)
+			}
+		}
+	}()
+}
+
+// Flush appends the buffered lines to their blobs
+func (w *AzureBlobWriter) Flush() error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	w.mu.Lock()
+	buffers := w.buffers
+	w.buffers = make(map[string]*bytes.Buffer)
+	w.mu.Unlock()
+
+	var firstErr error
+	for name, buf := range buffers {
+		if err := w.upload(name, buf.Bytes()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// upload creates the append blob if needed and appends data in blocks
+func (w *AzureBlobWriter) upload(name string, data []byte) error {
+	if !w.created[name] {
+		if err := w.createBlob(name); err != nil {
+			return err
+		}
+		w.created[name] = true
+	}
+
+	for len(data) > 0 {
+		n := len(data)
+		if n > maxAppendBlock {
+			n = maxAppendBlock
+		}
+		if err := w.appendBlock(name, data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// createBlob creates an empty append blob, keeping it when it already exists
+func (w *AzureBlobWriter) createBlob(name string) error {
+	req, err := http.NewRequest(http.MethodPut, w.blobURL(name, ""), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-ms-blob-type", "AppendBlob")
+	req.Header.Set("If-None-Match", "*")
+
+	return w.do(req, http.StatusCreated, http.StatusConflict)
+}
+
+// appendBlock appends data to the end of the blob
+func (w *AzureBlobWriter) appendBlock(name string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, w.blobURL(name, "comp=appendblock"), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	return w.do(req, http.StatusCreated)
+}
+
+// blobURL builds the URL of the blob with the SAS token
+func (w *AzureBlobWriter) blobURL(name, query string) string {
+	if query != "" {
+		query += "&"
+	}
+	return w.containerURL + "/" + url.PathEscape(name) + "?" + query + w.sasToken
+}
+
+// do sends the request and checks the status is one of the expected
+func (w *AzureBlobWriter) do(req *http.Request, expected ...int) error {
+	req.Header.Set("x-ms-version", apiVersion)
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("azure: %s %s returned %s", req.Method, req.URL.Path, resp.Status)
+}
+
+// Close stops the uploader and uploads the remaining lines
+func (w *AzureBlobWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	w.start.Do(func() {})
+	w.closeOnce.Do(func() { close(w.done) })
+	w.wg.Wait()
+
+	return w.Flush()
+}
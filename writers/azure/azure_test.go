@@ -0,0 +1,46 @@
+package azure
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAzureBlobWriterWithoutFlushInterval(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		appended []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "comp=appendblock") {
+			body, _ := ioutil.ReadAll(r.Body)
+			mu.Lock()
+			appended = append(appended, string(body))
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	w, err := NewAzureBlobWriter(srv.URL+"/logs", "sv=token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.FlushInterval = 0
+
+	if _, err := w.Write([]byte("INFO: started\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(appended) != 1 || appended[0] != "INFO: started\n" {
+		t.Errorf("appended %q, want the line once on Close", appended)
+	}
+}
@@ -0,0 +1,5 @@
+// Package eventlog writes log lines to the Windows Event Log.
+//
+// On other platforms the writer is a no-op, so services can wire it up
+// without build tags of their own.
+package eventlog
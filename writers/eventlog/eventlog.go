@@ -0,0 +1,68 @@
+package eventlog
+
+import (
+	"errors"
+	"io"
+
+	"github.com/codingmechanics/applogger"
+)
+
+// ErrClosed is returned when writing to a closed EventLogWriter
+var ErrClosed = errors.New("eventlog: writer is closed")
+
+// event types of the Event Log entries
+const (
+	typeInfo = iota
+	typeWarning
+	typeError
+)
+
+// Level returns a writer that reports to the Event Log with the event type of level,
+// w must have been created by NewEventLogWriter
+func Level(w io.Writer, level int32) io.Writer {
+	if ew, ok := w.(*EventLogWriter); ok {
+		return levelWriter{w: ew, eventType: eventType(level)}
+	}
+	return w
+}
+
+// levelWriter writes to an EventLogWriter with a fixed event type
+type levelWriter struct {
+	w         *EventLogWriter
+	eventType int
+}
+
+func (lw levelWriter) Write(p []byte) (int, error) {
+	return lw.w.write(lw.eventType, p)
+}
+
+// eventType maps an applogger level to an Event Log event type
+func eventType(level int32) int {
+	switch level {
+	case applogger.LevelWarn:
+		return typeWarning
+	case applogger.LevelError, applogger.LevelFatal:
+		return typeError
+	default:
+		return typeInfo
+	}
+}
+
+// NewWindowsServiceLogger sends the Error and Warning lines of l to the Event Log
+// under source, instead of their current output. Call it after l is started.
+func NewWindowsServiceLogger(l *applogger.Logger, source string) error {
+	w, err := NewEventLogWriter(source, 1)
+	if err != nil {
+		return err
+	}
+
+	if err := l.SetOutput(applogger.LevelError, Level(w, applogger.LevelError)); err != nil {
+		w.Close()
+		return err
+	}
+	if err := l.SetOutput(applogger.LevelWarn, Level(w, applogger.LevelWarn)); err != nil {
+		w.Close()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build !windows
+// +build !windows
+
+package eventlog
+
+import (
+	"io"
+	"sync"
+)
+
+// EventLogWriter discards the lines, there is no Event Log on this platform
+type EventLogWriter struct {
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewEventLogWriter returns a writer that discards the lines, there is no
+// Event Log on this platform
+func NewEventLogWriter(source string, eid uint32) (io.WriteCloser, error) {
+	return &EventLogWriter{}, nil
+}
+
+// Write discards p
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	return w.write(typeInfo, p)
+}
+
+// write discards p
+func (w *EventLogWriter) write(eventType int, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+	return len(p), nil
+}
+
+// Close closes the writer
+func (w *EventLogWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	return nil
+}
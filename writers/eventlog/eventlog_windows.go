@@ -0,0 +1,80 @@
+//go:build windows
+// +build windows
+
+package eventlog
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// EventLogWriter reports every Write to the Event Log as a single event.
+// Lines written through Write without a level are informational events.
+type EventLogWriter struct {
+	eid uint32
+
+	mu     sync.Mutex
+	log    *eventlog.Log
+	closed bool
+}
+
+// NewEventLogWriter opens the Event Log for source, eid is the event id of
+// every event written. Register the source with eventlog.InstallAsEventCreate
+// so the Event Viewer shows the messages without a warning.
+func NewEventLogWriter(source string, eid uint32) (io.WriteCloser, error) {
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventLogWriter{
+		eid: eid,
+		log: log,
+	}, nil
+}
+
+// Write reports p as an informational event
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	return w.write(typeInfo, p)
+}
+
+// write reports p as one event of eventType
+func (w *EventLogWriter) write(eventType int, p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	var err error
+	switch eventType {
+	case typeWarning:
+		err = w.log.Warning(w.eid, msg)
+	case typeError:
+		err = w.log.Error(w.eid, msg)
+	default:
+		err = w.log.Info(w.eid, msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the Event Log handle
+func (w *EventLogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.log.Close()
+}
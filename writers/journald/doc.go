@@ -0,0 +1,5 @@
+// Package journald writes log lines to the systemd journal.
+//
+// The writer speaks the native journal protocol over the journald socket,
+// so neither cgo nor go-systemd is needed. It is only available on Linux.
+package journald
@@ -0,0 +1,142 @@
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/codingmechanics/applogger"
+)
+
+// socketPath is where journald listens for native protocol datagrams
+const socketPath = "/run/systemd/journal/socket"
+
+// syslog priorities used for the PRIORITY field
+const (
+	priorityCrit    = 2
+	priorityErr     = 3
+	priorityWarning = 4
+	priorityInfo    = 6
+	priorityDebug   = 7
+)
+
+// ErrClosed is returned when writing to a closed JournaldWriter
+var ErrClosed = errors.New("journald: writer is closed")
+
+// JournaldWriter sends every Write to the journal as a single entry.
+// Lines written through Write without a level get the info priority.
+type JournaldWriter struct {
+	identifier string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+// NewJournaldWriter connects to the journal socket, identifier is written as
+// SYSLOG_IDENTIFIER so the entries can be queried with journalctl -t identifier.
+func NewJournaldWriter(identifier string) (*JournaldWriter, error) {
+	conn, err := net.Dial("unixgram", socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournaldWriter{
+		identifier: identifier,
+		conn:       conn,
+	}, nil
+}
+
+// Write sends p to the journal with the info priority
+func (w *JournaldWriter) Write(p []byte) (int, error) {
+	return w.write(priorityInfo, p)
+}
+
+// Level returns a writer that sends to the journal with the syslog priority of level
+func (w *JournaldWriter) Level(level int32) io.Writer {
+	return levelWriter{w: w, priority: Priority(level)}
+}
+
+// levelWriter writes to a JournaldWriter with a fixed priority
+type levelWriter struct {
+	w        *JournaldWriter
+	priority int
+}
+
+func (lw levelWriter) Write(p []byte) (int, error) {
+	return lw.w.write(lw.priority, p)
+}
+
+// Priority maps an applogger level to a syslog priority
+func Priority(level int32) int {
+	switch level {
+	case applogger.LevelTrace, applogger.LevelDebug:
+		return priorityDebug
+	case applogger.LevelInfo:
+		return priorityInfo
+	case applogger.LevelWarn:
+		return priorityWarning
+	case applogger.LevelFatal:
+		return priorityCrit
+	default:
+		return priorityErr
+	}
+}
+
+// write sends p as one journal entry
+func (w *JournaldWriter) write(priority int, p []byte) (int, error) {
+	var b bytes.Buffer
+	appendField(&b, "PRIORITY", strconv.Itoa(priority))
+	if w.identifier != "" {
+		appendField(&b, "SYSLOG_IDENTIFIER", w.identifier)
+	}
+	appendField(&b, "MESSAGE", strings.TrimRight(string(p), "\n"))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if _, err := w.conn.Write(b.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// appendField encodes a field of the native protocol. Values holding a new line
+// use the binary form: the name, a new line, the little endian uint64 size and the value.
+func appendField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// Close closes the connection to the journal
+func (w *JournaldWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.conn.Close()
+}
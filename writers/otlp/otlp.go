@@ -0,0 +1,265 @@
+// Package otlp exports log lines to an OpenTelemetry collector using the
+// OTLP/HTTP protobuf encoding, e.g. http://otel-collector:4318/v1/logs
+package otlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codingmechanics/applogger"
+)
+
+const (
+	// DefaultBatchSize is the number of records exported in one request
+	DefaultBatchSize = 512
+
+	// DefaultFlushInterval is how often buffered records are exported
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// ErrClosed is returned when writing to a closed OTLPWriter
+var ErrClosed = errors.New("otlp: writer is closed")
+
+// SeverityNumber is the OpenTelemetry log record severity
+type SeverityNumber int32
+
+// severities used by applogger, see the OpenTelemetry log data model
+const (
+	SeverityUnspecified SeverityNumber = 0
+	SeverityTrace       SeverityNumber = 1
+	SeverityDebug       SeverityNumber = 5
+	SeverityInfo        SeverityNumber = 9
+	SeverityWarn        SeverityNumber = 13
+	SeverityError       SeverityNumber = 17
+	SeverityFatal       SeverityNumber = 21
+)
+
+// Severity maps an applogger level to the OpenTelemetry severity
+func Severity(level int32) SeverityNumber {
+	switch level {
+	case applogger.LevelTrace:
+		return SeverityTrace
+	case applogger.LevelDebug:
+		return SeverityDebug
+	case applogger.LevelInfo:
+		return SeverityInfo
+	case applogger.LevelWarn:
+		return SeverityWarn
+	case applogger.LevelError:
+		return SeverityError
+	case applogger.LevelFatal:
+		return SeverityFatal
+	default:
+		return SeverityUnspecified
+	}
+}
+
+// severityText is the short name sent along with the severity number
+func (s SeverityNumber) severityText() string {
+	switch s {
+	case SeverityTrace:
+		return "TRACE"
+	case SeverityDebug:
+		return "DEBUG"
+	case SeverityInfo:
+		return "INFO"
+	case SeverityWarn:
+		return "WARN"
+	case SeverityError:
+		return "ERROR"
+	case SeverityFatal:
+		return "FATAL"
+	default:
+		return ""
+	}
+}
+
+// record is a buffered log line
+type record struct {
+	time     time.Time
+	severity SeverityNumber
+	body     string
+}
+
+// OTLPWriter batches log lines and exports them to a collector.
+// Records are sent once BatchSize lines are buffered or every FlushInterval,
+// whichever comes first, a FlushInterval of 0 only sends full batches and
+// the records left on Close. Both can be changed until the first Write.
+type OTLPWriter struct {
+	BatchSize     int
+	FlushInterval time.Duration
+
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+
+	mu      sync.Mutex
+	records []record
+	closed  bool
+
+	sendMu    sync.Mutex
+	start     sync.Once
+	closeOnce sync.Once
+	flush     chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewOTLPWriter creates a writer exporting to the OTLP/HTTP logs endpoint.
+// headers are sent with every request, e.g. for collector authentication.
+func NewOTLPWriter(endpoint string, headers map[string]string) (*OTLPWriter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("otlp: unsupported endpoint scheme %q", u.Scheme)
+	}
+
+	h := make(map[string]string, len(headers))
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	return &OTLPWriter{
+		BatchSize:     DefaultBatchSize,
+		FlushInterval: DefaultFlushInterval,
+		endpoint:      endpoint,
+		headers:       h,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		flush:         make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// Write buffers p as a single record with no severity
+func (w *OTLPWriter) Write(p []byte) (int, error) {
+	return w.write(SeverityUnspecified, p)
+}
+
+// Level returns a writer that buffers records with the severity of the level
+func (w *OTLPWriter) Level(level int32) io.Writer {
+	return levelWriter{w: w, severity: Severity(level)}
+}
+
+// levelWriter writes to an OTLPWriter with a fixed severity
+type levelWriter struct {
+	w        *OTLPWriter
+	severity SeverityNumber
+}
+
+func (lw levelWriter) Write(p []byte) (int, error) {
+	return lw.w.write(lw.severity, p)
+}
+
+// write buffers a record and wakes the exporter once the batch is full
+func (w *OTLPWriter) write(severity SeverityNumber, p []byte) (int, error) {
+	w.start.Do(w.run)
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrClosed
+	}
+	w.records = append(w.records, record{
+		time:     time.Now(),
+		severity: severity,
+		body:     strings.TrimRight(string(p), "\n"),
+	})
+	full := len(w.records) >= w.BatchSize
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.flush <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// run starts the goroutine exporting the buffered records
+func (w *OTLPWriter) run() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		var tick <-chan time.Time
+		if w.FlushInterval > 0 {
+			ticker := time.NewTicker(w.FlushInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-tick:
+			case <-w.flush:
+			}
+
+			if err := w.Flush(); err != nil {
+				fmt.Fprintf(os.Stderr, "otlp: export failed: %s\n", err)
+			}
+		}
+	}()
+}
+
+// Flush exports the buffered records
+func (w *OTLPWriter) Flush() error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	w.mu.Lock()
+	records := w.records
+	w.records = nil
+	w.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(encodeRequest(records)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp: collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close stops the exporter and sends the remaining records
+func (w *OTLPWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	w.start.Do(func() {})
+	w.closeOnce.Do(func() { close(w.done) })
+	w.wg.Wait()
+
+	return w.Flush()
+}
@@ -0,0 +1,36 @@
+package otlp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOTLPWriterWithoutFlushInterval(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer srv.Close()
+
+	w, err := NewOTLPWriter(srv.URL+"/v1/logs", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.FlushInterval = 0
+
+	if _, err := w.Write([]byte("INFO: started\n")); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("exported %d requests before the batch was full", n)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("exported %d requests on Close, want 1", n)
+	}
+}
@@ -0,0 +1,84 @@
+package otlp
+
+// Hand written protobuf encoding of the subset of
+// opentelemetry.proto.collector.logs.v1.ExportLogsServiceRequest used by
+// OTLPWriter. Field numbers are taken from the OTLP .proto files.
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// protoBuffer appends protobuf encoded fields
+type protoBuffer []byte
+
+func (b *protoBuffer) varint(v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v)|0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+func (b *protoBuffer) tag(field, wire int) {
+	b.varint(uint64(field)<<3 | uint64(wire))
+}
+
+func (b *protoBuffer) varintField(field int, v uint64) {
+	b.tag(field, wireVarint)
+	b.varint(v)
+}
+
+func (b *protoBuffer) fixed64Field(field int, v uint64) {
+	b.tag(field, wireFixed64)
+	for i := 0; i < 8; i++ {
+		*b = append(*b, byte(v>>(8*uint(i))))
+	}
+}
+
+func (b *protoBuffer) bytesField(field int, v []byte) {
+	b.tag(field, wireBytes)
+	b.varint(uint64(len(v)))
+	*b = append(*b, v...)
+}
+
+func (b *protoBuffer) stringField(field int, s string) {
+	b.bytesField(field, []byte(s))
+}
+
+// encodeRequest encodes the records as an ExportLogsServiceRequest with a
+// single ResourceLogs and ScopeLogs
+func encodeRequest(records []record) []byte {
+	var scope protoBuffer
+	scope.stringField(1, "applogger") // InstrumentationScope.name
+
+	var scopeLogs protoBuffer
+	scopeLogs.bytesField(1, scope) // ScopeLogs.scope
+	for _, r := range records {
+		scopeLogs.bytesField(2, encodeRecord(r)) // ScopeLogs.log_records
+	}
+
+	var resourceLogs protoBuffer
+	resourceLogs.bytesField(2, scopeLogs) // ResourceLogs.scope_logs
+
+	var req protoBuffer
+	req.bytesField(1, resourceLogs) // ExportLogsServiceRequest.resource_logs
+	return req
+}
+
+// encodeRecord encodes a LogRecord with a string body
+func encodeRecord(r record) []byte {
+	var body protoBuffer
+	body.stringField(1, r.body) // AnyValue.string_value
+
+	var b protoBuffer
+	b.fixed64Field(1, uint64(r.time.UnixNano())) // time_unix_nano
+	if r.severity != SeverityUnspecified {
+		b.varintField(2, uint64(r.severity))        // severity_number
+		b.stringField(3, r.severity.severityText()) // severity_text
+	}
+	b.bytesField(5, body)                         // body
+	b.fixed64Field(11, uint64(r.time.UnixNano())) // observed_time_unix_nano
+	return b
+}